@@ -2,19 +2,57 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/redis/go-redis/extra/redisotel/v9"
 
 	"github.com/cavidyrm/instawall/config"
+	analyticsdelivery "github.com/cavidyrm/instawall/internal/analytics/delivery/http"
+	analyticsRepo "github.com/cavidyrm/instawall/internal/analytics/repository/postgres"
+	analyticsRedis "github.com/cavidyrm/instawall/internal/analytics/repository/redis"
+	analyticsUsecase "github.com/cavidyrm/instawall/internal/analytics/usecase"
+	analyticsWorker "github.com/cavidyrm/instawall/internal/analytics/worker"
 	// --- New, Feature-Specific Imports ---
 	categorydelivery "github.com/cavidyrm/instawall/internal/category/delivery/http"
 	categoryRepo "github.com/cavidyrm/instawall/internal/category/repository/postgres"
 	categoryUsecase "github.com/cavidyrm/instawall/internal/category/usecase"
+	categoryWorker "github.com/cavidyrm/instawall/internal/category/worker"
+	feeddelivery "github.com/cavidyrm/instawall/internal/feed/delivery/http"
+	feedDomain "github.com/cavidyrm/instawall/internal/feed/domain"
+	feedRepo "github.com/cavidyrm/instawall/internal/feed/repository/postgres"
+	feedRedis "github.com/cavidyrm/instawall/internal/feed/repository/redis"
+	feedUsecase "github.com/cavidyrm/instawall/internal/feed/usecase"
+	feedWorker "github.com/cavidyrm/instawall/internal/feed/worker"
+	"github.com/cavidyrm/instawall/internal/health"
+	"github.com/cavidyrm/instawall/internal/logging"
+	"github.com/cavidyrm/instawall/internal/metrics"
+	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
 	pagedelivery "github.com/cavidyrm/instawall/internal/page/delivery/http"
+	pageDomain "github.com/cavidyrm/instawall/internal/page/domain"
 	pageRepo "github.com/cavidyrm/instawall/internal/page/repository/postgres"
 	pageUsecase "github.com/cavidyrm/instawall/internal/page/usecase"
+	pageWorker "github.com/cavidyrm/instawall/internal/page/worker"
+	promotiondelivery "github.com/cavidyrm/instawall/internal/promotion/delivery/http"
+	promotionRepo "github.com/cavidyrm/instawall/internal/promotion/repository/postgres"
+	promotionUsecase "github.com/cavidyrm/instawall/internal/promotion/usecase"
+	"github.com/cavidyrm/instawall/internal/ratelimit"
+	tagdelivery "github.com/cavidyrm/instawall/internal/tag/delivery/http"
+	tagRepo "github.com/cavidyrm/instawall/internal/tag/repository/postgres"
+	tagUsecase "github.com/cavidyrm/instawall/internal/tag/usecase"
+	"github.com/cavidyrm/instawall/internal/tracing"
+	"github.com/cavidyrm/instawall/internal/validator"
 	// --- User Imports ---
 	userdelivery "github.com/cavidyrm/instawall/internal/user/delivery/http"
 	userRepo "github.com/cavidyrm/instawall/internal/user/repository/postgres"
@@ -23,59 +61,218 @@ import (
 	// --- Common Packages ---
 	"github.com/cavidyrm/instawall/pkg/database"
 	"github.com/cavidyrm/instawall/pkg/filestore"
+	"github.com/cavidyrm/instawall/pkg/i18n"
+	"github.com/cavidyrm/instawall/pkg/instagram"
 	"github.com/cavidyrm/instawall/pkg/migration"
 )
 
 func main() {
 	// 1. Load Configuration
-	cfg, err := config.LoadConfig(".")
+	configFile := flag.String("config", os.Getenv(config.EnvPrefix+"_CONFIG"), "path to the config file (default ./config.yaml if it exists)")
+	printConfig := flag.Bool("print-config", false, "print the effective configuration with secrets redacted and exit")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(*configFile)
 	if err != nil {
 		log.Fatalf("could not load config: %v", err)
 	}
+	if *printConfig {
+		fmt.Print(cfg)
+		return
+	}
+	appMiddleware.JWTSecret = []byte(cfg.Auth.JWTSecret)
+
+	logger, err := logging.New(cfg.Log, os.Stdout)
+	if err != nil {
+		log.Fatalf("could not create logger: %v", err)
+	}
+	// Packages without an injected logger, and the stdlib log package, use
+	// the same handler.
+	slog.SetDefault(logger)
+	fatal := func(msg string, err error) {
+		logger.Error(msg, "err", err)
+		os.Exit(1)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		fatal("could not set up tracing", err)
+	}
 
 	// 2. Initialize External Services
 	db, err := database.NewPostgresDB(cfg.Postgres)
 	if err != nil {
-		log.Fatalf("could not initialize postgres db: %v", err)
+		fatal("could not initialize postgres db", err)
 	}
-	defer db.Close()
+	metrics.RegisterDB(db.DB, "postgres")
 
 	migration.Run(db, cfg.Postgres.DBName)
 
 	rdb := database.NewRedisClient(cfg.Redis)
+	rdb.AddHook(metrics.RedisHook())
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		fatal("could not instrument redis", err)
+	}
 	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
-		log.Fatalf("could not connect to redis: %v", err)
+		fatal("could not connect to redis", err)
 	}
 
 	fs, err := filestore.NewFileStore(cfg.MinIO)
 	if err != nil {
-		log.Fatalf("could not initialize minio filestore: %v", err)
+		fatal("could not initialize minio filestore", err)
 	}
 
 	// 3. Initialize Echo
 	e := echo.New()
-	e.Use(middleware.Logger())
+	e.HideBanner = true
+	e.HidePort = true
+	e.HTTPErrorHandler = appMiddleware.HTTPErrorHandler
+	e.Validator = validator.NewValidator()
+	if e.IPExtractor, err = appMiddleware.IPExtractor(cfg.Server.TrustedProxies); err != nil {
+		fatal("invalid trusted proxies", err)
+	}
+	e.Use(appMiddleware.RequestIDMiddleware(logger))
+	e.Use(appMiddleware.TracingMiddleware(cfg.Tracing.ServiceName))
+	e.Use(appMiddleware.MetricsMiddleware)
+	e.Use(appMiddleware.RequestLoggerMiddleware(logger))
 	e.Use(middleware.Recover())
+	if cfg.RateLimit.Enabled {
+		var store ratelimit.Store = ratelimit.NewRedisStore(rdb)
+		if cfg.RateLimit.Store == "memory" {
+			store = ratelimit.NewMemoryStore()
+		}
+		e.Use(appMiddleware.RateLimitMiddleware(store, cfg.RateLimit))
+	}
+
+	negotiator, err := i18n.NewNegotiator(cfg.I18n.DefaultLocale, cfg.I18n.SupportedLocales)
+	if err != nil {
+		fatal("invalid i18n config", err)
+	}
+	e.Use(appMiddleware.LocaleMiddleware(negotiator))
+
+	checker := health.NewChecker(cfg.Server.HealthCheckTimeout)
+	checker.Add("postgres", db.PingContext)
+	checker.Add("redis", func(ctx context.Context) error { return rdb.Ping(ctx).Err() })
+	checker.Add("minio", fs.Ping)
+	health.RegisterHandlers(e, checker)
 
 	// 4. Initialize Repositories
-	userRepository := userRepo.NewUserRepository(db)
-	otpRepository := redisRepo.NewOTPRepository(rdb)
-	pageRepository := pageRepo.NewPageRepository(db)
-	categoryRepository := categoryRepo.NewCategoryRepository(db)
+	userRepository := userRepo.NewUserRepository(db, logger)
+	otpRepository := redisRepo.NewOTPRepository(rdb, logger)
+	pageRepository := pageRepo.NewPageRepository(db, logger)
+	claimRepository := pageRepo.NewClaimRepository(db, logger)
+	categoryRepository := categoryRepo.NewCategoryRepository(db, logger)
+	statsRepository := analyticsRepo.NewStatsRepository(db, logger)
+	eventBuffer := analyticsRedis.NewEventBuffer(rdb, logger)
+	feedRepository := feedRepo.NewFeedRepository(db, logger)
+	rankingStore := feedRedis.NewRankingStore(rdb, logger)
+	promotionRepository := promotionRepo.NewPromotionRepository(db, logger)
+	tagRepository := tagRepo.NewTagRepository(db, logger)
 
 	// 5. Initialize Usecases
-	userUC := userUsecase.NewUserUsecase(userRepository, otpRepository)
-	pageUC := pageUsecase.NewPageUsecase(pageRepository, fs)
-	categoryUC := categoryUsecase.NewCategoryUsecase(categoryRepository, fs)
+	userUC := userUsecase.NewUserUsecase(userRepository, otpRepository, logger)
+	promotionUC := promotionUsecase.NewPromotionUsecase(promotionRepository, pageRepository, logger)
+	pageUC := pageUsecase.NewPageUsecase(pageRepository, fs, promotionUC, cfg.Promotions.Slots, pageDomain.ImageLimits{
+		MaxCount: cfg.Pages.MaxImages,
+		MaxSize:  cfg.Pages.MaxImageSize,
+	}, logger)
+	bioVerifier := instagram.NewBioVerifier(&http.Client{Timeout: 10 * time.Second}, "")
+	claimUC := pageUsecase.NewClaimUsecase(pageRepository, claimRepository, bioVerifier, logger)
+	categoryUC := categoryUsecase.NewCategoryUsecase(categoryRepository, fs, cfg.Categories.RestoreWindow, logger)
+	analyticsUC := analyticsUsecase.NewAnalyticsUsecase(eventBuffer, statsRepository, pageRepository, promotionUC, logger)
+	feedUC := feedUsecase.NewFeedUsecase(feedRepository, rankingStore, pageRepository, feedDomain.TrendingWeights{
+		View:     cfg.Feed.ViewWeight,
+		Click:    cfg.Feed.ClickWeight,
+		Favorite: cfg.Feed.FavoriteWeight,
+		HalfLife: cfg.Feed.HalfLife,
+		Window:   cfg.Feed.Window,
+		MaxPages: cfg.Feed.MaxPages,
+	}, 3*cfg.Feed.RecomputeInterval, logger)
+
+	tagUC := tagUsecase.NewTagUsecase(tagRepository, pageRepository, cfg.Tags.MaxPerPage, logger)
 
 	// 6. Register deliverys
-	userdelivery.Registerdeliverys(e, userUC)
-	pagedelivery.RegisterPagedeliverys(e, pageUC)
-	categorydelivery.RegisterCategorydeliverys(e, categoryUC)
+	userdelivery.RegisterHandlers(e, userUC)
+	pagedelivery.RegisterPageHandlers(e, pageUC, analyticsUC, logger)
+	pagedelivery.RegisterClaimHandlers(e, claimUC)
+	categorydelivery.RegisterCategoryHandlers(e, categoryUC)
+	analyticsdelivery.RegisterAnalyticsHandlers(e, analyticsUC)
+	feeddelivery.RegisterFeedHandlers(e, feedUC)
+	promotiondelivery.RegisterPromotionHandlers(e, promotionUC)
+	tagdelivery.RegisterTagHandlers(e, tagUC)
+
+	// 7. Start Background Workers
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	startWorker := func(run func(context.Context)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workerCtx)
+		}()
+	}
+	if cfg.LinkChecker.Enabled {
+		startWorker(pageWorker.NewLinkChecker(pageRepository, nil, cfg.LinkChecker, logger).Run)
+	}
+	startWorker(analyticsWorker.NewFlusher(analyticsUC, cfg.Analytics.FlushInterval, logger).Run)
+	startWorker(feedWorker.NewRanker(feedUC, cfg.Feed.RecomputeInterval, logger).Run)
+	startWorker(categoryWorker.NewPurger(categoryUC, cfg.Categories.PurgeInterval, logger).Run)
+	startWorker(pageWorker.NewPublisher(pageRepository, cfg.Pages.PublishInterval, cfg.Pages.PublishBatchSize, logger).Run)
+
+	// 8. Start Server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	serverErr := make(chan error, 2)
+	go func() {
+		logger.Info("starting server", "addr", cfg.Server.Port)
+		serverErr <- e.Start(cfg.Server.Port)
+	}()
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", metrics.Handler())
+	admin := &http.Server{Addr: cfg.Server.AdminPort, Handler: adminMux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		logger.Info("starting admin server", "addr", cfg.Server.AdminPort)
+		serverErr <- admin.ListenAndServe()
+	}()
+	var startErr error
+	select {
+	case <-ctx.Done():
+		logger.Info("shutting down", "drain_timeout", cfg.Server.ShutdownTimeout)
+	case err := <-serverErr:
+		startErr = err
+	}
+
+	// 9. Shut Down: fail readiness and give load balancers time to notice
+	// and stop sending traffic, drain the requests in flight, then stop the
+	// workers, which may still flush to Redis and Postgres.
+	stop() // a second signal exits immediately
+	checker.ShuttingDown()
+	if startErr == nil && cfg.Server.ReadinessGrace > 0 {
+		logger.Info("waiting for load balancers to stop sending traffic", "grace", cfg.Server.ReadinessGrace)
+		time.Sleep(cfg.Server.ReadinessGrace)
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		logger.Error("drain requests", "err", err)
+	}
+	if err := admin.Shutdown(shutdownCtx); err != nil {
+		logger.Error("stop admin server", "err", err)
+	}
+	stopWorkers()
+	workers.Wait()
+	if err := rdb.Close(); err != nil {
+		logger.Error("close redis", "err", err)
+	}
+	if err := db.Close(); err != nil {
+		logger.Error("close postgres", "err", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Error("flush traces", "err", err)
+	}
 
-	// 7. Start Server
-	log.Printf("Starting server on port %s", cfg.Server.Port)
-	if err := e.Start(cfg.Server.Port); err != nil {
-		e.Logger.Fatal(err)
+	if startErr != nil && !errors.Is(startErr, http.ErrServerClosed) {
+		fatal("server failed", startErr)
 	}
+	logger.Info("shutdown complete")
 }
@@ -1,20 +1,64 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
 	"github.com/spf13/viper"
 )
 
 // Config holds all configuration for the application.
 type Config struct {
 	Server   ServerConfig   `mapstructure:"server"`
+	Log      LogConfig      `mapstructure:"log"`
+	Tracing  TracingConfig  `mapstructure:"tracing"`
 	Postgres PostgresConfig `mapstructure:"postgres"`
 	Redis    RedisConfig    `mapstructure:"redis"`
-	MinIO    MinIOConfig    `mapstructure:"minio"` // <-- This is now correctly included.
+	MinIO    MinIOConfig    `mapstructure:"minio"`
+	Auth     AuthConfig     `mapstructure:"auth"`
+
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+
+	LinkChecker LinkCheckerConfig `mapstructure:"link_checker"`
+	Analytics   AnalyticsConfig   `mapstructure:"analytics"`
+	Feed        FeedConfig        `mapstructure:"feed"`
+	Promotions  PromotionsConfig  `mapstructure:"promotions"`
+	Categories  CategoriesConfig  `mapstructure:"categories"`
+	I18n        I18nConfig        `mapstructure:"i18n"`
+	Tags        TagsConfig        `mapstructure:"tags"`
+	Pages       PagesConfig       `mapstructure:"pages"`
 }
 
 // ServerConfig holds server-specific settings.
 type ServerConfig struct {
-	Port string `mapstructure:"port"`
+	Port               string        `mapstructure:"port"`
+	AdminPort          string        `mapstructure:"admin_port"`           // serves /metrics; keep it private
+	ShutdownTimeout    time.Duration `mapstructure:"shutdown_timeout"`     // how long in-flight requests may take to drain
+	ReadinessGrace     time.Duration `mapstructure:"readiness_grace"`      // how long /readyz fails before the listener closes
+	HealthCheckTimeout time.Duration `mapstructure:"health_check_timeout"` // per readiness probe
+	// TrustedProxies lists the CIDR ranges of reverse proxies whose
+	// X-Forwarded-For header is believed. With none, the client IP is the
+	// address of the connection.
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// LogConfig controls the structured logger.
+type LogConfig struct {
+	Level  string `mapstructure:"level"`  // debug, info, warn or error
+	Format string `mapstructure:"format"` // json or text
+}
+
+// TracingConfig controls where OpenTelemetry spans are exported.
+type TracingConfig struct {
+	Exporter    string  `mapstructure:"exporter"` // none, stdout or otlp
+	Endpoint    string  `mapstructure:"endpoint"` // OTLP/HTTP collector, host:port
+	Insecure    bool    `mapstructure:"insecure"` // use plain HTTP for the collector
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+	ServiceName string  `mapstructure:"service_name"`
 }
 
 // PostgresConfig holds PostgreSQL connection details.
@@ -22,7 +66,7 @@ type PostgresConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
 	User     string `mapstructure:"user"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" secret:"true"`
 	DBName   string `mapstructure:"dbname"`
 	SSLMode  string `mapstructure:"sslmode"`
 }
@@ -31,7 +75,7 @@ type PostgresConfig struct {
 type RedisConfig struct {
 	Host     string `mapstructure:"host"`
 	Port     string `mapstructure:"port"`
-	Password string `mapstructure:"password"`
+	Password string `mapstructure:"password" secret:"true"`
 	DB       int    `mapstructure:"db"`
 }
 
@@ -39,24 +83,219 @@ type RedisConfig struct {
 type MinIOConfig struct {
 	Endpoint   string `mapstructure:"endpoint"`
 	AccessKey  string `mapstructure:"access_key"`
-	SecretKey  string `mapstructure:"secret_key"`
+	SecretKey  string `mapstructure:"secret_key" secret:"true"`
 	UseSSL     bool   `mapstructure:"use_ssl"`
 	BucketName string `mapstructure:"bucket_name"`
 }
 
-// LoadConfig reads configuration from file or environment variables.
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
+// AuthConfig holds the key access tokens are signed with.
+type AuthConfig struct {
+	JWTSecret string `mapstructure:"jwt_secret" secret:"true"`
+}
+
+// RateLimitConfig limits how many requests each user, or each client IP for
+// anonymous requests, may make to every group of routes. A request counts
+// against the first group with a route matching it, or against Default if
+// none does.
+type RateLimitConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Store   string `mapstructure:"store"` // redis, or memory for a single instance
+
+	Groups  []RateLimitGroup `mapstructure:"groups"`
+	Default RateLimitRule    `mapstructure:"default"`
+}
+
+// RateLimitGroup applies a rule to a group of routes. Routes are given as
+// registered, optionally preceded by a method, e.g. "POST /pages/:id/images"
+// or "/auth/*"; a trailing * matches any path under the prefix, and a route
+// without a method, or with *, matches every method.
+type RateLimitGroup struct {
+	Name          string   `mapstructure:"name"`
+	Routes        []string `mapstructure:"routes"`
+	RateLimitRule `mapstructure:",squash"`
+}
+
+// RateLimitRule allows Limit requests per Window, in bursts of up to Limit.
+// A limit of 0 turns the rule off.
+type RateLimitRule struct {
+	Limit  int           `mapstructure:"limit"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+// LinkCheckerConfig controls the background worker that checks page links.
+type LinkCheckerConfig struct {
+	Enabled          bool          `mapstructure:"enabled"`
+	PollInterval     time.Duration `mapstructure:"poll_interval"`     // how often to look for due pages
+	RecheckInterval  time.Duration `mapstructure:"recheck_interval"`  // wait after a healthy check
+	RetryInterval    time.Duration `mapstructure:"retry_interval"`    // first wait after a failed check
+	MaxBackoff       time.Duration `mapstructure:"max_backoff"`       // cap for retry and per-host backoff
+	Timeout          time.Duration `mapstructure:"timeout"`           // per-request timeout
+	Concurrency      int           `mapstructure:"concurrency"`       // parallel requests
+	BatchSize        int           `mapstructure:"batch_size"`        // pages fetched per poll
+	PerHostInterval  time.Duration `mapstructure:"per_host_interval"` // minimum gap between requests to one host
+	FailureThreshold int           `mapstructure:"failure_threshold"` // consecutive failures before has_issue is set
+	UserAgent        string        `mapstructure:"user_agent"`
+}
+
+// AnalyticsConfig controls how buffered page events are flushed.
+type AnalyticsConfig struct {
+	FlushInterval time.Duration `mapstructure:"flush_interval"`
+}
+
+// FeedConfig controls how the trending and featured feeds are ranked.
+type FeedConfig struct {
+	RecomputeInterval time.Duration `mapstructure:"recompute_interval"`
+	HalfLife          time.Duration `mapstructure:"half_life"` // age at which activity counts half
+	Window            time.Duration `mapstructure:"window"`    // activity older than this is ignored
+	ViewWeight        float64       `mapstructure:"view_weight"`
+	ClickWeight       float64       `mapstructure:"click_weight"`
+	FavoriteWeight    float64       `mapstructure:"favorite_weight"`
+	MaxPages          int           `mapstructure:"max_pages"` // size of the trending feed
+}
+
+// PromotionsConfig controls where sponsored pages appear in listings.
+type PromotionsConfig struct {
+	Slots []int `mapstructure:"slots"` // zero-based positions in each listing response
+}
+
+// CategoriesConfig controls how long deleted categories can be restored.
+type CategoriesConfig struct {
+	RestoreWindow time.Duration `mapstructure:"restore_window"` // deleted categories are purged after this
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+}
+
+// I18nConfig lists the locales content can be served in.
+type I18nConfig struct {
+	DefaultLocale    string   `mapstructure:"default_locale"` // locale of the untranslated titles and descriptions
+	SupportedLocales []string `mapstructure:"supported_locales"`
+}
+
+// TagsConfig limits the tags users put on their pages.
+type TagsConfig struct {
+	MaxPerPage int `mapstructure:"max_per_page"`
+}
+
+// PagesConfig limits the image galleries of pages and configures the
+// publisher of scheduled pages.
+type PagesConfig struct {
+	MaxImages    int   `mapstructure:"max_images"`
+	MaxImageSize int64 `mapstructure:"max_image_size"` // bytes
+
+	PublishInterval  time.Duration `mapstructure:"publish_interval"` // how often scheduled pages are checked
+	PublishBatchSize int           `mapstructure:"publish_batch_size"`
+}
 
-	viper.AutomaticEnv()
+// EnvPrefix is the prefix of environment variables that override settings.
+// A key maps to a variable by upper-casing it and replacing dots with
+// underscores, e.g. postgres.password is INSTAWALL_POSTGRES_PASSWORD.
+const EnvPrefix = "INSTAWALL"
+
+// fileSuffix marks a variable that names a file holding the value instead,
+// e.g. INSTAWALL_POSTGRES_PASSWORD_FILE=/run/secrets/db_password.
+const fileSuffix = "_FILE"
+
+// LoadConfig builds the configuration from the defaults, the config file and
+// the environment, in increasing order of precedence, and validates it.
+// With file empty, config.yaml in the working directory is used if it
+// exists; a file that is named explicitly must exist.
+func LoadConfig(file string) (Config, error) {
+	v := viper.New()
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	// Viper only looks up environment variables for keys it knows, so every
+	// key needs a default.
+	defaults := Default()
+	walk(reflect.ValueOf(&defaults).Elem(), "", func(key string, value reflect.Value, _ reflect.StructField) {
+		v.SetDefault(key, value.Interface())
+	})
+
+	if file != "" {
+		v.SetConfigFile(file)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath(".")
+	}
+	if err := v.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if file != "" || !errors.As(err, &notFound) {
+			return Config{}, fmt.Errorf("read config file: %w", err)
+		}
+	}
 
-	err = viper.ReadInConfig()
-	if err != nil {
-		return
+	if err := readSecretFiles(v); err != nil {
+		return Config{}, err
 	}
 
-	err = viper.Unmarshal(&config)
-	return
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return Config{}, fmt.Errorf("decode config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config:\n%w", err)
+	}
+	return config, nil
+}
+
+// EnvVar returns the environment variable that overrides key.
+func EnvVar(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// readSecretFiles sets every key whose variable has a _FILE counterpart to
+// the contents of that file, without a trailing newline.
+func readSecretFiles(v *viper.Viper) error {
+	var errs []error
+	for _, key := range v.AllKeys() {
+		name := EnvVar(key)
+		path, ok := os.LookupEnv(name + fileSuffix)
+		if !ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			errs = append(errs, fmt.Errorf("%s and %s%s are both set", name, name, fileSuffix))
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s%s: %w", name, fileSuffix, err))
+			continue
+		}
+		v.Set(key, strings.TrimRight(string(data), "\r\n"))
+	}
+	return errors.Join(errs...)
+}
+
+// String lists the settings one per line as "key = value", with secrets
+// redacted, so that the effective configuration can be logged safely.
+func (c Config) String() string {
+	var b strings.Builder
+	walk(reflect.ValueOf(c), "", func(key string, value reflect.Value, field reflect.StructField) {
+		shown := fmt.Sprint(value.Interface())
+		if field.Tag.Get("secret") == "true" && !value.IsZero() {
+			shown = "[redacted]"
+		}
+		fmt.Fprintf(&b, "%s = %s\n", key, shown)
+	})
+	return b.String()
+}
+
+// walk calls fn for every setting under v, a Config or one of its sections,
+// with its key as it appears in the config file.
+func walk(v reflect.Value, prefix string, fn func(key string, value reflect.Value, field reflect.StructField)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("mapstructure"), ",")
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if value := v.Field(i); value.Kind() == reflect.Struct {
+			walk(value, key, fn)
+		} else {
+			fn(key, value, field)
+		}
+	}
 }
@@ -0,0 +1,103 @@
+package config
+
+import "time"
+
+// Default returns the settings used for anything the config file and the
+// environment leave out. They suit local development; credentials and the
+// JWT secret have to be set for anything else.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:               ":8080",
+			AdminPort:          ":9090",
+			ShutdownTimeout:    15 * time.Second,
+			ReadinessGrace:     5 * time.Second,
+			HealthCheckTimeout: 2 * time.Second,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "redis",
+			Groups: []RateLimitGroup{
+				{
+					Name:          "auth",
+					Routes:        []string{"/auth/*"},
+					RateLimitRule: RateLimitRule{Limit: 10, Window: time.Minute},
+				},
+				{
+					// Routes that accept images, whether or not a request
+					// sends any.
+					Name: "uploads",
+					Routes: []string{
+						"POST /pages", "POST /pages/:id/images",
+						"POST /categories", "PUT /categories/:id", "PATCH /categories/:id",
+					},
+					RateLimitRule: RateLimitRule{Limit: 20, Window: time.Hour},
+				},
+				{
+					Name:          "writes",
+					Routes:        []string{"POST /*", "PUT /*", "PATCH /*", "DELETE /*"},
+					RateLimitRule: RateLimitRule{Limit: 60, Window: time.Minute},
+				},
+			},
+			Default: RateLimitRule{Limit: 600, Window: time.Minute},
+		},
+		Log: LogConfig{Level: "info", Format: "json"},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			Endpoint:    "localhost:4318",
+			SampleRatio: 1,
+			ServiceName: "instawall",
+		},
+		Postgres: PostgresConfig{
+			Host:    "localhost",
+			Port:    "5432",
+			User:    "user",
+			DBName:  "test",
+			SSLMode: "disable",
+		},
+		Redis: RedisConfig{Host: "localhost", Port: "6379"},
+		MinIO: MinIOConfig{
+			Endpoint:   "localhost:9000",
+			BucketName: "my-app-bucket",
+		},
+		LinkChecker: LinkCheckerConfig{
+			Enabled:          true,
+			PollInterval:     time.Minute,
+			RecheckInterval:  24 * time.Hour,
+			RetryInterval:    time.Hour,
+			MaxBackoff:       24 * time.Hour,
+			Timeout:          10 * time.Second,
+			Concurrency:      4,
+			BatchSize:        100,
+			PerHostInterval:  2 * time.Second,
+			FailureThreshold: 3,
+			UserAgent:        "instawall-linkchecker/1.0",
+		},
+		Analytics: AnalyticsConfig{FlushInterval: time.Minute},
+		Feed: FeedConfig{
+			RecomputeInterval: 5 * time.Minute,
+			HalfLife:          72 * time.Hour,
+			Window:            14 * 24 * time.Hour,
+			ViewWeight:        1,
+			ClickWeight:       3,
+			FavoriteWeight:    5,
+			MaxPages:          500,
+		},
+		Promotions: PromotionsConfig{Slots: []int{2, 9}},
+		Categories: CategoriesConfig{
+			RestoreWindow: 30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		I18n: I18nConfig{
+			DefaultLocale:    "fa",
+			SupportedLocales: []string{"fa", "en"},
+		},
+		Tags: TagsConfig{MaxPerPage: 10},
+		Pages: PagesConfig{
+			MaxImages:        10,
+			MaxImageSize:     5 << 20,
+			PublishInterval:  time.Minute,
+			PublishBatchSize: 100,
+		},
+	}
+}
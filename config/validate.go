@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Validate checks the settings and reports every invalid one, each on its
+// own line, rather than stopping at the first.
+func (c Config) Validate() error {
+	var p problems
+
+	if _, _, err := net.SplitHostPort(c.Server.Port); err != nil {
+		p.add("server.port", "must be an address such as :8080")
+	}
+	if _, _, err := net.SplitHostPort(c.Server.AdminPort); err != nil {
+		p.add("server.admin_port", "must be an address such as :9090")
+	} else if c.Server.AdminPort == c.Server.Port {
+		p.add("server.admin_port", "must differ from server.port")
+	}
+	p.positiveDuration("server.shutdown_timeout", c.Server.ShutdownTimeout)
+	p.positiveDuration("server.health_check_timeout", c.Server.HealthCheckTimeout)
+	if c.Server.ReadinessGrace < 0 {
+		p.add("server.readiness_grace", "must not be negative")
+	}
+	for _, cidr := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			p.add("server.trusted_proxies", fmt.Sprintf("%q is not a CIDR range such as 10.0.0.0/8", cidr))
+		}
+	}
+
+	levels := []string{"debug", "info", "warn", "error"}
+	if !slices.Contains(levels, strings.ToLower(c.Log.Level)) {
+		p.add("log.level", fmt.Sprintf("must be one of %v", levels))
+	}
+	formats := []string{"json", "text"}
+	if !slices.Contains(formats, strings.ToLower(c.Log.Format)) {
+		p.add("log.format", fmt.Sprintf("must be one of %v", formats))
+	}
+
+	exporters := []string{"none", "stdout", "otlp"}
+	if !slices.Contains(exporters, c.Tracing.Exporter) {
+		p.add("tracing.exporter", fmt.Sprintf("must be one of %v", exporters))
+	}
+	if c.Tracing.Exporter == "otlp" {
+		p.required("tracing.endpoint", c.Tracing.Endpoint)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		p.add("tracing.sample_ratio", "must be between 0 and 1")
+	}
+	p.required("tracing.service_name", c.Tracing.ServiceName)
+
+	p.required("postgres.host", c.Postgres.Host)
+	p.required("postgres.port", c.Postgres.Port)
+	p.required("postgres.user", c.Postgres.User)
+	p.required("postgres.dbname", c.Postgres.DBName)
+	sslModes := []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+	if !slices.Contains(sslModes, c.Postgres.SSLMode) {
+		p.add("postgres.sslmode", fmt.Sprintf("must be one of %v", sslModes))
+	}
+
+	p.required("redis.host", c.Redis.Host)
+	p.required("redis.port", c.Redis.Port)
+	if c.Redis.DB < 0 {
+		p.add("redis.db", "must not be negative")
+	}
+
+	p.required("minio.endpoint", c.MinIO.Endpoint)
+	p.required("minio.access_key", c.MinIO.AccessKey)
+	p.required("minio.secret_key", c.MinIO.SecretKey)
+	p.required("minio.bucket_name", c.MinIO.BucketName)
+
+	p.required("auth.jwt_secret", c.Auth.JWTSecret)
+
+	if rl := c.RateLimit; rl.Enabled {
+		stores := []string{"redis", "memory"}
+		if !slices.Contains(stores, rl.Store) {
+			p.add("rate_limit.store", fmt.Sprintf("must be one of %v", stores))
+		}
+		names := make(map[string]bool, len(rl.Groups))
+		for i, g := range rl.Groups {
+			key := fmt.Sprintf("rate_limit.groups[%d]", i)
+			switch {
+			case g.Name == "" || g.Name == "default":
+				p.add(key+".name", `must be set and not "default"`)
+			case names[g.Name]:
+				p.add(key+".name", fmt.Sprintf("%q is used by another group", g.Name))
+			}
+			names[g.Name] = true
+			if len(g.Routes) == 0 {
+				p.add(key+".routes", "must list at least one route")
+			}
+			for _, route := range g.Routes {
+				if !validRoute(route) {
+					p.add(key+".routes", fmt.Sprintf("%q is not a route such as \"POST /pages\" or \"/auth/*\"", route))
+				}
+			}
+			p.rateLimitRule(key, g.RateLimitRule)
+		}
+		p.rateLimitRule("rate_limit.default", rl.Default)
+	}
+
+	if lc := c.LinkChecker; lc.Enabled {
+		p.positiveDuration("link_checker.poll_interval", lc.PollInterval)
+		p.positiveDuration("link_checker.recheck_interval", lc.RecheckInterval)
+		p.positiveDuration("link_checker.retry_interval", lc.RetryInterval)
+		p.positiveDuration("link_checker.max_backoff", lc.MaxBackoff)
+		p.positiveDuration("link_checker.timeout", lc.Timeout)
+		if lc.PerHostInterval < 0 {
+			p.add("link_checker.per_host_interval", "must not be negative")
+		}
+		p.positive("link_checker.concurrency", lc.Concurrency)
+		p.positive("link_checker.batch_size", lc.BatchSize)
+		p.positive("link_checker.failure_threshold", lc.FailureThreshold)
+	}
+
+	p.positiveDuration("analytics.flush_interval", c.Analytics.FlushInterval)
+
+	p.positiveDuration("feed.recompute_interval", c.Feed.RecomputeInterval)
+	p.positiveDuration("feed.half_life", c.Feed.HalfLife)
+	p.positiveDuration("feed.window", c.Feed.Window)
+	p.nonNegative("feed.view_weight", c.Feed.ViewWeight)
+	p.nonNegative("feed.click_weight", c.Feed.ClickWeight)
+	p.nonNegative("feed.favorite_weight", c.Feed.FavoriteWeight)
+	p.positive("feed.max_pages", c.Feed.MaxPages)
+
+	for _, slot := range c.Promotions.Slots {
+		if slot < 0 {
+			p.add("promotions.slots", "must not contain negative positions")
+			break
+		}
+	}
+
+	p.positiveDuration("categories.restore_window", c.Categories.RestoreWindow)
+	p.positiveDuration("categories.purge_interval", c.Categories.PurgeInterval)
+
+	p.required("i18n.default_locale", c.I18n.DefaultLocale)
+
+	p.positive("tags.max_per_page", c.Tags.MaxPerPage)
+
+	p.positive("pages.max_images", c.Pages.MaxImages)
+	if c.Pages.MaxImageSize <= 0 {
+		p.add("pages.max_image_size", "must be positive")
+	}
+	p.positiveDuration("pages.publish_interval", c.Pages.PublishInterval)
+	p.positive("pages.publish_batch_size", c.Pages.PublishBatchSize)
+
+	return errors.Join(p...)
+}
+
+// problems collects validation failures, each naming the setting and the
+// variable that overrides it.
+type problems []error
+
+// add reports an invalid setting, naming the variable that overrides it.
+// Settings within lists, such as rate_limit.groups[0].limit, can only be set
+// in the config file.
+func (p *problems) add(key, msg string) {
+	if strings.Contains(key, "[") {
+		*p = append(*p, fmt.Errorf("%s: %s", key, msg))
+		return
+	}
+	*p = append(*p, fmt.Errorf("%s (%s): %s", key, EnvVar(key), msg))
+}
+
+func (p *problems) required(key, value string) {
+	if value == "" {
+		p.add(key, "must be set")
+	}
+}
+
+func (p *problems) positive(key string, n int) {
+	if n <= 0 {
+		p.add(key, "must be positive")
+	}
+}
+
+func (p *problems) nonNegative(key string, n float64) {
+	if n < 0 {
+		p.add(key, "must not be negative")
+	}
+}
+
+func (p *problems) positiveDuration(key string, d time.Duration) {
+	if d <= 0 {
+		p.add(key, "must be a positive duration such as 30s or 5m")
+	}
+}
+
+// validRoute reports whether route is a path, optionally preceded by an HTTP
+// method or *.
+func validRoute(route string) bool {
+	fields := strings.Fields(route)
+	if len(fields) == 2 {
+		methods := []string{"*", "GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+		if !slices.Contains(methods, fields[0]) {
+			return false
+		}
+		fields = fields[1:]
+	}
+	return len(fields) == 1 && strings.HasPrefix(fields[0], "/")
+}
+
+func (p *problems) rateLimitRule(key string, r RateLimitRule) {
+	if r.Limit < 0 {
+		p.add(key+".limit", "must not be negative")
+	}
+	if r.Limit > 0 {
+		p.positiveDuration(key+".window", r.Window)
+	}
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/analytics/usecase"
+	"github.com/cavidyrm/instawall/internal/apperror"
+	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const dayLayout = "2006-01-02"
+
+type AnalyticsHandler struct {
+	analyticsUsecase *usecase.AnalyticsUsecase
+}
+
+func RegisterAnalyticsHandlers(e *echo.Echo, uc *usecase.AnalyticsUsecase) {
+	h := &AnalyticsHandler{analyticsUsecase: uc}
+	pageGroup := e.Group("/pages")
+
+	// Public outbound redirect that counts clicks; sponsored listings link
+	// here with ?sponsored=true so the page's running promotion is counted too
+	pageGroup.GET("/:id/go", h.GoToPage)
+
+	// Owner-only statistics
+	pageGroup.GET("/:id/stats", h.GetPageStats, appMiddleware.JWTAuthMiddleware)
+}
+
+// Request/Response Structs
+type DailyStatResponse struct {
+	Day    string `json:"day"`
+	Views  int64  `json:"views"`
+	Clicks int64  `json:"clicks"`
+}
+type PageStatsResponse struct {
+	PageID      uuid.UUID           `json:"page_id"`
+	From        string              `json:"from"`
+	To          string              `json:"to"`
+	TotalViews  int64               `json:"total_views"`
+	TotalClicks int64               `json:"total_clicks"`
+	Days        []DailyStatResponse `json:"days"`
+}
+
+// --- Handler Methods ---
+
+func (h *AnalyticsHandler) GoToPage(c echo.Context) error {
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	sponsored := c.QueryParam("sponsored") == "true"
+	link, err := h.analyticsUsecase.RecordClick(c.Request().Context(), pageID, sponsored, c.Request().UserAgent())
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("Cache-Control", "no-store")
+	return c.Redirect(http.StatusFound, link)
+}
+
+func (h *AnalyticsHandler) GetPageStats(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -29)
+	if s := c.QueryParam("from"); s != "" {
+		if from, err = time.Parse(dayLayout, s); err != nil {
+			return apperror.Validation("invalid from date, expected YYYY-MM-DD")
+		}
+	}
+	if s := c.QueryParam("to"); s != "" {
+		if to, err = time.Parse(dayLayout, s); err != nil {
+			return apperror.Validation("invalid to date, expected YYYY-MM-DD")
+		}
+	}
+
+	stats, err := h.analyticsUsecase.GetPageStats(c.Request().Context(), pageID, userID, from, to)
+	if err != nil {
+		return err
+	}
+
+	resp := PageStatsResponse{
+		PageID: pageID,
+		From:   from.Format(dayLayout),
+		To:     to.Format(dayLayout),
+		Days:   make([]DailyStatResponse, len(stats)),
+	}
+	for i, s := range stats {
+		resp.Days[i] = DailyStatResponse{Day: s.Day.Format(dayLayout), Views: s.Views, Clicks: s.Clicks}
+		resp.TotalViews += s.Views
+		resp.TotalClicks += s.Clicks
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event kinds recorded for a page.
+const (
+	EventView  = "view"
+	EventClick = "click"
+)
+
+// EventCount is a number of buffered events of one kind for a page on a day.
+type EventCount struct {
+	PageID uuid.UUID
+	Day    time.Time
+	Kind   string
+	Count  int64
+}
+
+// DailyStat holds the view and click totals of a page for one day.
+type DailyStat struct {
+	PageID uuid.UUID `db:"page_id"`
+	Day    time.Time `db:"day"`
+	Views  int64     `db:"views"`
+	Clicks int64     `db:"clicks"`
+}
@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/analytics/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// StatsRepository provides a database implementation for page analytics.
+type StatsRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewStatsRepository creates a new StatsRepository. A nil logger means
+// slog.Default().
+func NewStatsRepository(db *sqlx.DB, logger *slog.Logger) *StatsRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &StatsRepository{db: db, logger: logger}
+}
+
+// AddDailyStats adds the given totals to the stored daily aggregates in a
+// single statement.
+func (r *StatsRepository) AddDailyStats(ctx context.Context, stats []domain.DailyStat) error {
+	if len(stats) == 0 {
+		return nil
+	}
+	pageIDs := make([]string, len(stats))
+	days := make([]string, len(stats))
+	views := make([]int64, len(stats))
+	clicks := make([]int64, len(stats))
+	for i, s := range stats {
+		pageIDs[i] = s.PageID.String()
+		days[i] = s.Day.Format("2006-01-02")
+		views[i] = s.Views
+		clicks[i] = s.Clicks
+	}
+
+	// Events for pages deleted since they were buffered are dropped by the join.
+	query := `INSERT INTO page_daily_stats (page_id, day, views, clicks)
+			  SELECT s.page_id, s.day, s.views, s.clicks
+			  FROM unnest($1::uuid[], $2::date[], $3::bigint[], $4::bigint[]) AS s(page_id, day, views, clicks)
+			  JOIN pages p ON p.id = s.page_id
+			  ON CONFLICT (page_id, day) DO UPDATE
+			  SET views = page_daily_stats.views + EXCLUDED.views, clicks = page_daily_stats.clicks + EXCLUDED.clicks`
+	res, err := r.db.ExecContext(ctx, query, pq.Array(pageIDs), pq.Array(days), pq.Array(views), pq.Array(clicks))
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n < int64(len(stats)) {
+		r.logger.DebugContext(ctx, "dropped events of deleted pages", "page_days", int64(len(stats))-n)
+	}
+	return nil
+}
+
+// GetDailyStats retrieves the stored daily totals of a page between two days, inclusive.
+func (r *StatsRepository) GetDailyStats(ctx context.Context, pageID uuid.UUID, from, to time.Time) ([]domain.DailyStat, error) {
+	var stats []domain.DailyStat
+	query := `SELECT * FROM page_daily_stats WHERE page_id = $1 AND day BETWEEN $2 AND $3 ORDER BY day ASC`
+	err := r.db.SelectContext(ctx, &stats, query, pageID, from, to)
+	return stats, err
+}
@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/analytics/domain"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	pendingKey  = "analytics:events"
+	flushingKey = "analytics:events:flushing"
+	lockKey     = "analytics:events:lock"
+	lockTTL     = time.Minute
+	dayLayout   = "2006-01-02"
+)
+
+// errLockLost means the flush lock expired or was taken over before the
+// batch was acknowledged, so another flush may store the batch again.
+var errLockLost = errors.New("analytics buffer lock lost")
+
+// ackScript discards the drained batch and releases the lock, but only for
+// the holder of the lock whose token is given.
+var ackScript = redis.NewScript(`
+if redis.call('GET', KEYS[2]) ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1], KEYS[2])
+return 1
+`)
+
+// releaseScript releases the lock if it is still held with the given token.
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+	return 0
+end
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+// EventBuffer accumulates page events in a Redis hash until they are flushed
+// to the database.
+type EventBuffer struct {
+	rdb    *redis.Client
+	logger *slog.Logger
+}
+
+// NewEventBuffer creates a new EventBuffer. A nil logger means
+// slog.Default().
+func NewEventBuffer(rdb *redis.Client, logger *slog.Logger) *EventBuffer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &EventBuffer{rdb: rdb, logger: logger}
+}
+
+// Incr counts one event of the given kind for a page on the given day.
+func (b *EventBuffer) Incr(ctx context.Context, pageID uuid.UUID, day time.Time, kind string) error {
+	return b.rdb.HIncrBy(ctx, pendingKey, field(pageID, day, kind), 1).Err()
+}
+
+// Drain moves the buffered events aside and returns them with the token of
+// the flush lock it took. It returns no events if another flush holds the
+// lock. A batch left behind by a flush that failed before Ack is returned
+// again. Call Ack with the token once the events are stored, or Release if
+// they could not be.
+func (b *EventBuffer) Drain(ctx context.Context) ([]domain.EventCount, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	locked, err := b.rdb.SetNX(ctx, lockKey, token, lockTTL).Result()
+	if err != nil || !locked {
+		return nil, "", err
+	}
+
+	exists, err := b.rdb.Exists(ctx, flushingKey).Result()
+	if err != nil {
+		return nil, "", b.releaseAfter(ctx, token, err)
+	}
+	if exists == 0 {
+		if err := b.rdb.Rename(ctx, pendingKey, flushingKey).Err(); err != nil {
+			if isNoSuchKey(err) {
+				return nil, "", b.Release(ctx, token)
+			}
+			return nil, "", b.releaseAfter(ctx, token, err)
+		}
+	}
+
+	raw, err := b.rdb.HGetAll(ctx, flushingKey).Result()
+	if err != nil {
+		return nil, "", b.releaseAfter(ctx, token, err)
+	}
+	counts := make([]domain.EventCount, 0, len(raw))
+	for f, v := range raw {
+		ec, ok := parseField(f)
+		if !ok {
+			b.logger.WarnContext(ctx, "skip malformed buffered event", "field", f)
+			continue
+		}
+		if ec.Count, err = strconv.ParseInt(v, 10, 64); err != nil {
+			b.logger.WarnContext(ctx, "skip malformed buffered event", "field", f, "count", v)
+			continue
+		}
+		counts = append(counts, ec)
+	}
+	if len(counts) == 0 {
+		return nil, "", b.Ack(ctx, token)
+	}
+	return counts, token, nil
+}
+
+// Ack discards the batch returned by the Drain that handed out token and
+// releases the lock. It leaves the batch alone if the lock is no longer held
+// with that token.
+func (b *EventBuffer) Ack(ctx context.Context, token string) error {
+	acked, err := ackScript.Run(ctx, b.rdb, []string{flushingKey, lockKey}, token).Int()
+	if err != nil {
+		return err
+	}
+	if acked == 0 {
+		return errLockLost
+	}
+	return nil
+}
+
+// Release gives up the lock without discarding the drained batch, so the
+// next flush retries it. A lock held with another token is left alone.
+func (b *EventBuffer) Release(ctx context.Context, token string) error {
+	return releaseScript.Run(ctx, b.rdb, []string{lockKey}, token).Err()
+}
+
+// releaseAfter releases the lock after Drain failed with err, which it
+// returns.
+func (b *EventBuffer) releaseAfter(ctx context.Context, token string, err error) error {
+	if relErr := b.Release(ctx, token); relErr != nil {
+		b.logger.WarnContext(ctx, "release analytics buffer lock", "err", relErr)
+	}
+	return err
+}
+
+// newToken returns a random value identifying one holder of the lock.
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func field(pageID uuid.UUID, day time.Time, kind string) string {
+	return pageID.String() + "|" + day.UTC().Format(dayLayout) + "|" + kind
+}
+
+func parseField(f string) (domain.EventCount, bool) {
+	parts := strings.Split(f, "|")
+	if len(parts) != 3 {
+		return domain.EventCount{}, false
+	}
+	pageID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return domain.EventCount{}, false
+	}
+	day, err := time.Parse(dayLayout, parts[1])
+	if err != nil {
+		return domain.EventCount{}, false
+	}
+	return domain.EventCount{PageID: pageID, Day: day, Kind: parts[2]}, true
+}
+
+func isNoSuchKey(err error) bool {
+	var rerr redis.Error
+	return errors.As(err, &rerr) && strings.Contains(rerr.Error(), "no such key")
+}
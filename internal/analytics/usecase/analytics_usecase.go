@@ -0,0 +1,233 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/analytics/domain"
+	"github.com/cavidyrm/instawall/internal/apperror"
+	pageDomain "github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/pkg/instagram"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/internal/analytics/usecase")
+
+var errNoLink = apperror.New(apperror.KindNotFound, "page_link_not_found", "page has no valid link")
+
+// maxStatsRange bounds how many days a single stats request may cover.
+const maxStatsRange = 366 * 24 * time.Hour
+
+// --- Interface Definitions for Dependencies ---
+type EventBuffer interface {
+	Incr(ctx context.Context, pageID uuid.UUID, day time.Time, kind string) error
+	Drain(ctx context.Context) ([]domain.EventCount, string, error)
+	Ack(ctx context.Context, token string) error
+	Release(ctx context.Context, token string) error
+}
+type StatsRepository interface {
+	AddDailyStats(ctx context.Context, stats []domain.DailyStat) error
+	GetDailyStats(ctx context.Context, pageID uuid.UUID, from, to time.Time) ([]domain.DailyStat, error)
+}
+type PageReader interface {
+	GetPageByID(ctx context.Context, pageID uuid.UUID) (*pageDomain.Page, error)
+}
+type PromotionClickRecorder interface {
+	RecordClick(ctx context.Context, pageID uuid.UUID) error
+}
+
+// --- Usecase Implementation ---
+type AnalyticsUsecase struct {
+	buffer     EventBuffer
+	statsRepo  StatsRepository
+	pageRepo   PageReader
+	promotions PromotionClickRecorder
+	logger     *slog.Logger
+}
+
+// NewAnalyticsUsecase creates an AnalyticsUsecase. A nil logger means
+// slog.Default().
+func NewAnalyticsUsecase(b EventBuffer, sr StatsRepository, pr PageReader, promotions PromotionClickRecorder, logger *slog.Logger) *AnalyticsUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &AnalyticsUsecase{buffer: b, statsRepo: sr, pageRepo: pr, promotions: promotions, logger: logger}
+}
+
+// --- Usecase Methods ---
+
+// RecordView counts a view of a page unless the user agent looks like a bot.
+func (uc *AnalyticsUsecase) RecordView(ctx context.Context, pageID uuid.UUID, userAgent string) error {
+	ctx, span := tracer.Start(ctx, "AnalyticsUsecase.RecordView")
+	defer span.End()
+
+	if IsBot(userAgent) {
+		return nil
+	}
+	return uc.buffer.Incr(ctx, pageID, time.Now(), domain.EventView)
+}
+
+// RecordClick counts an outbound click, and a click on the page's running
+// promotion if the click came from a sponsored slot, and returns the link to
+// redirect to. Only published pages without issues can be clicked through, and the
+// link is built from the page's Instagram handle rather than taken from the
+// stored link, so the redirect can only lead to Instagram. A failure to
+// count the click does not prevent the redirect.
+func (uc *AnalyticsUsecase) RecordClick(ctx context.Context, pageID uuid.UUID, sponsored bool, userAgent string) (string, error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsUsecase.RecordClick")
+	defer span.End()
+
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return "", apperror.FromDB(err, "page")
+	}
+	if p.Status != pageDomain.PageStatusPublished || p.HasIssue {
+		return "", apperror.NotFound("page")
+	}
+	if p.InstagramHandle == nil {
+		return "", errNoLink
+	}
+	// Rows from before handles were validated may hold anything.
+	handle, err := instagram.ParseHandle("@" + *p.InstagramHandle)
+	if err != nil {
+		return "", errNoLink
+	}
+	if !IsBot(userAgent) {
+		if err := uc.buffer.Incr(ctx, pageID, time.Now(), domain.EventClick); err != nil {
+			uc.logger.WarnContext(ctx, "record page click", "page_id", pageID, "err", err)
+		}
+		if sponsored && uc.promotions != nil {
+			if err := uc.promotions.RecordClick(ctx, pageID); err != nil {
+				uc.logger.WarnContext(ctx, "record promotion click", "page_id", pageID, "err", err)
+			}
+		}
+	}
+	return instagram.ProfileURL(handle), nil
+}
+
+// Flush moves buffered events into the daily aggregates table and returns
+// the number of page-days written.
+func (uc *AnalyticsUsecase) Flush(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsUsecase.Flush")
+	defer span.End()
+
+	counts, token, err := uc.buffer.Drain(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	stats := aggregate(counts)
+	if err := uc.statsRepo.AddDailyStats(ctx, stats); err != nil {
+		if relErr := uc.buffer.Release(ctx, token); relErr != nil {
+			uc.logger.WarnContext(ctx, "release analytics buffer lock", "err", relErr)
+		}
+		return 0, err
+	}
+	return len(stats), uc.buffer.Ack(ctx, token)
+}
+
+// GetPageStats returns one entry per day between from and to (inclusive) for
+// a page owned by userID, with zeroes for days without events.
+func (uc *AnalyticsUsecase) GetPageStats(ctx context.Context, pageID, userID uuid.UUID, from, to time.Time) ([]domain.DailyStat, error) {
+	ctx, span := tracer.Start(ctx, "AnalyticsUsecase.GetPageStats")
+	defer span.End()
+
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	if p.UserID != userID {
+		return nil, apperror.Forbidden("user does not own this page")
+	}
+	from, to = truncateDay(from), truncateDay(to)
+	if to.Before(from) {
+		return nil, apperror.Invalid("invalid_range", "from is after to")
+	}
+	if to.Sub(from) > maxStatsRange {
+		return nil, apperror.Invalid("invalid_range", fmt.Sprintf("at most %d days may be requested", int(maxStatsRange.Hours()/24)))
+	}
+
+	stored, err := uc.statsRepo.GetDailyStats(ctx, pageID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[time.Time]domain.DailyStat, len(stored))
+	for _, s := range stored {
+		byDay[truncateDay(s.Day)] = s
+	}
+
+	var out []domain.DailyStat
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		s, ok := byDay[d]
+		if !ok {
+			s = domain.DailyStat{PageID: pageID}
+		}
+		s.Day = d
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// aggregate folds per-kind event counts into per-page daily totals.
+func aggregate(counts []domain.EventCount) []domain.DailyStat {
+	type key struct {
+		pageID uuid.UUID
+		day    time.Time
+	}
+	byKey := make(map[key]*domain.DailyStat)
+	var order []key
+	for _, ec := range counts {
+		k := key{ec.PageID, truncateDay(ec.Day)}
+		s, ok := byKey[k]
+		if !ok {
+			s = &domain.DailyStat{PageID: ec.PageID, Day: k.day}
+			byKey[k] = s
+			order = append(order, k)
+		}
+		switch ec.Kind {
+		case domain.EventView:
+			s.Views += ec.Count
+		case domain.EventClick:
+			s.Clicks += ec.Count
+		}
+	}
+	stats := make([]domain.DailyStat, len(order))
+	for i, k := range order {
+		stats[i] = *byKey[k]
+	}
+	return stats
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// botMarkers are lower-cased user agent fragments of crawlers, link
+// previewers and scripted clients.
+var botMarkers = []string{
+	"bot", "crawl", "spider", "slurp", "facebookexternalhit", "embedly",
+	"preview", "headless", "lighthouse", "curl/", "wget/", "python-requests",
+	"python-urllib", "go-http-client", "okhttp", "java/", "libwww", "httpclient",
+}
+
+// IsBot reports whether a user agent should be excluded from analytics.
+func IsBot(userAgent string) bool {
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	if ua == "" {
+		return true
+	}
+	for _, m := range botMarkers {
+		if strings.Contains(ua, m) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// StatsFlusher moves buffered events into the database.
+type StatsFlusher interface {
+	Flush(ctx context.Context) (int, error)
+}
+
+// Flusher periodically flushes buffered analytics events.
+type Flusher struct {
+	flusher  StatsFlusher
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewFlusher creates a new Flusher. A nil logger means slog.Default().
+func NewFlusher(f StatsFlusher, interval time.Duration, logger *slog.Logger) *Flusher {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Flusher{flusher: f, interval: interval, logger: logger.With("worker", "analytics_flusher")}
+}
+
+// Run flushes every interval until ctx is cancelled, then performs a final
+// flush so buffered events are not left waiting for the next instance.
+func (f *Flusher) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			finalCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if _, err := f.flusher.Flush(finalCtx); err != nil {
+				f.logger.ErrorContext(finalCtx, "final flush of analytics events", "err", err)
+			}
+			return
+		case <-ticker.C:
+			if _, err := f.flusher.Flush(ctx); err != nil && ctx.Err() == nil {
+				f.logger.ErrorContext(ctx, "flush analytics events", "err", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// Package apperror defines the typed errors usecases return to signal a
+// client-facing failure. The HTTP layer turns them into problem responses;
+// any other error is reported as an internal error without its details.
+package apperror
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Kind classifies an Error and determines its HTTP status.
+type Kind int
+
+const (
+	KindValidation Kind = iota + 1
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindGone
+	KindUnprocessable
+	KindRateLimited
+	KindPreconditionFailed
+)
+
+// Error is a client-facing error. Code is a stable, machine-readable
+// identifier; Message is safe to show to the client.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+
+	// Extra holds additional members for the response body, e.g. the ID of a
+	// conflicting resource.
+	Extra map[string]any
+	// RetryAfter is set on rate-limited errors.
+	RetryAfter time.Duration
+
+	cause error
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Unwrap returns the underlying error, if any.
+func (e *Error) Unwrap() error { return e.cause }
+
+// With returns a copy of e with an extra response member set.
+func (e *Error) With(key string, value any) *Error {
+	cp := *e
+	cp.Extra = make(map[string]any, len(e.Extra)+1)
+	for k, v := range e.Extra {
+		cp.Extra[k] = v
+	}
+	cp.Extra[key] = value
+	return &cp
+}
+
+// Wrap returns a copy of e that wraps cause. The cause is logged but never
+// sent to the client.
+func (e *Error) Wrap(cause error) *Error {
+	cp := *e
+	cp.cause = cause
+	return &cp
+}
+
+// New returns an Error of any kind.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// NotFound reports a missing resource, e.g. NotFound("page") has the code
+// "page_not_found".
+func NotFound(resource string) *Error {
+	return &Error{Kind: KindNotFound, Code: codeFor(resource) + "_not_found", Message: resource + " not found"}
+}
+
+// Forbidden reports that the caller may not perform an operation.
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Code: "forbidden", Message: message}
+}
+
+// Unauthorized reports missing or invalid credentials.
+func Unauthorized(message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: "unauthorized", Message: message}
+}
+
+// Conflict reports that an operation conflicts with the current state.
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+// Gone reports a resource that existed but is no longer available.
+func Gone(code, message string) *Error {
+	return &Error{Kind: KindGone, Code: code, Message: message}
+}
+
+// Unprocessable reports a well-formed request that cannot be carried out,
+// e.g. because a precondition checked outside the service failed.
+func Unprocessable(code, message string) *Error {
+	return &Error{Kind: KindUnprocessable, Code: code, Message: message}
+}
+
+// PreconditionFailed reports that a conditional request, e.g. one with
+// If-Match, does not match the current state of the resource.
+func PreconditionFailed(code, message string) *Error {
+	return &Error{Kind: KindPreconditionFailed, Code: code, Message: message}
+}
+
+// Validation reports invalid input.
+func Validation(message string) *Error {
+	return &Error{Kind: KindValidation, Code: "invalid_input", Message: message}
+}
+
+// Invalid reports invalid input with a specific code.
+func Invalid(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+// RateLimited reports that the caller must wait retryAfter before retrying.
+func RateLimited(message string, retryAfter time.Duration) *Error {
+	return &Error{Kind: KindRateLimited, Code: "rate_limited", Message: message, RetryAfter: retryAfter}
+}
+
+// As returns the *Error in err's chain, if any.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	ok := errors.As(err, &appErr)
+	return appErr, ok
+}
+
+// Is reports whether err is an Error of the given kind.
+func Is(err error, kind Kind) bool {
+	appErr, ok := As(err)
+	return ok && appErr.Kind == kind
+}
+
+// Postgres error codes translated by FromDB.
+const (
+	pqForeignKeyViolation = "23503"
+	pqUniqueViolation     = "23505"
+	pqCheckViolation      = "23514"
+)
+
+// FromDB translates database errors into typed errors: sql.ErrNoRows becomes
+// NotFound(resource), and unique, foreign key and check violations become
+// conflict or validation errors. Other errors, including nil, are returned
+// unchanged.
+func FromDB(err error, resource string) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(resource).Wrap(err)
+	}
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+	switch pqErr.Code {
+	case pqUniqueViolation:
+		return Conflict("already_exists", resource+" already exists").Wrap(err)
+	case pqForeignKeyViolation:
+		// Deleting or updating a row that others still reference, as
+		// opposed to referencing a row that does not exist.
+		if strings.HasPrefix(pqErr.Message, "update or delete") {
+			return Conflict(codeFor(resource)+"_in_use", resource+" is still in use").Wrap(err)
+		}
+		return Invalid("invalid_reference", "a referenced resource does not exist").Wrap(err)
+	case pqCheckViolation:
+		return Validation("invalid " + resource).Wrap(err)
+	}
+	return err
+}
+
+func codeFor(resource string) string {
+	return strings.ReplaceAll(resource, " ", "_")
+}
@@ -1,11 +1,11 @@
 package http
 
 import (
-	"fmt"
 	"github.com/google/uuid"
 	"net/http"
-	"strings"
 
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/category/domain"
 	"github.com/cavidyrm/instawall/internal/category/usecase"
 	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
 	"github.com/labstack/echo/v4"
@@ -19,37 +19,95 @@ func RegisterCategoryHandlers(e *echo.Echo, uc *usecase.CategoryUsecase) {
 	h := &CategoryHandler{categoryUsecase: uc}
 	categoryGroup := e.Group("/categories")
 
-	// Public routes to view categories
-	categoryGroup.GET("", h.GetAllCategories)
-	categoryGroup.GET("/:id", h.GetCategory)
+	// Public routes to view categories; admins may pass include_inactive=true
+	publicCategoryGroup := categoryGroup.Group("")
+	publicCategoryGroup.Use(appMiddleware.OptionalJWTAuthMiddleware)
+	publicCategoryGroup.GET("", h.GetAllCategories)
+	publicCategoryGroup.GET("/tree", h.GetCategoryTree)
+	publicCategoryGroup.GET("/:id", h.GetCategory)
 
 	// Admin-only routes to manage categories
 	adminCategoryGroup := categoryGroup.Group("")
 	adminCategoryGroup.Use(appMiddleware.JWTAuthMiddleware, appMiddleware.AdminOnlyMiddleware)
 	adminCategoryGroup.POST("", h.CreateCategory)
+	adminCategoryGroup.GET("/deleted", h.GetDeletedCategories)
+	adminCategoryGroup.POST("/:id/restore", h.RestoreCategory)
+	adminCategoryGroup.PUT("/order", h.ReorderCategories)
+	adminCategoryGroup.PATCH("/:id", h.UpdateCategory)
 	adminCategoryGroup.PUT("/:id", h.UpdateCategory)
 	adminCategoryGroup.DELETE("/:id", h.DeleteCategory)
+	adminCategoryGroup.GET("/:id/translations", h.GetTranslations)
+	adminCategoryGroup.PUT("/:id/translations/:locale", h.SetTranslation)
+	adminCategoryGroup.DELETE("/:id/translations/:locale", h.DeleteTranslation)
+}
+
+// --- Request DTOs ---
+
+// CreateCategoryRequest holds the fields of a new category, sent as a
+// multipart form with the image.
+type CreateCategoryRequest struct {
+	Title       string `form:"title" validate:"required,max=100"`
+	Slug        string `form:"slug" validate:"omitempty,max=120"`
+	Description string `form:"description" validate:"max=2000"`
+	ParentID    string `form:"parent_id" validate:"omitempty,uuid"`
+	IsActive    *bool  `form:"is_active"`
+}
+
+// UpdateCategoryRequest holds the fields of a category update, sent as JSON
+// or as a form. Omitted fields are left unchanged; an empty parent_id moves
+// the category to the top level.
+type UpdateCategoryRequest struct {
+	Title       *string `json:"title" form:"title" validate:"omitnil,notempty,max=100"`
+	Slug        string  `json:"slug" form:"slug" validate:"omitempty,max=120"`
+	Description *string `json:"description" form:"description" validate:"omitnil,max=2000"`
+	ParentID    *string `json:"parent_id" form:"parent_id"`
+	IsActive    *bool   `json:"is_active" form:"is_active"`
+}
+
+type TranslationRequest struct {
+	Title       string `json:"title" form:"title" validate:"required,max=100"`
+	Description string `json:"description" form:"description" validate:"max=2000"`
+}
+
+type reorderRequest struct {
+	Order []struct {
+		ID        uuid.UUID `json:"id" validate:"required"`
+		SortOrder int       `json:"sort_order"`
+	} `json:"order" validate:"required,min=1,dive"`
 }
 
 // --- Handler Methods ---
 
 func (h *CategoryHandler) CreateCategory(c echo.Context) error {
-	title := c.FormValue("title")
-	description := c.FormValue("description")
+	var req CreateCategoryRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
 
 	fileHeader, err := c.FormFile("image")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Image file is required")
+		return apperror.Validation("image file is required")
 	}
 	src, err := fileHeader.Open()
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "Failed to open image file")
+		return err
 	}
 	defer src.Close()
 
+	parentID, err := optionalUUID(req.ParentID)
+	if err != nil {
+		return apperror.Validation("invalid parent ID")
+	}
+
 	input := usecase.CreateCategoryInput{
-		Title:       title,
-		Description: description,
+		ParentID:    parentID,
+		IsActive:    req.IsActive,
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Description: req.Description,
 		ImageFile:   src,
 		ImageSize:   fileHeader.Size,
 		ImageName:   fileHeader.Filename,
@@ -57,54 +115,88 @@ func (h *CategoryHandler) CreateCategory(c echo.Context) error {
 
 	newCategory, err := h.categoryUsecase.CreateCategory(c.Request().Context(), input)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Failed to create category: %v", err))
+		return err
 	}
 
+	appMiddleware.SetETag(c, newCategory.Version)
 	return c.JSON(http.StatusCreated, newCategory)
 }
 
+// GetCategory looks a category up by ID or, failing that, by slug.
 func (h *CategoryHandler) GetCategory(c echo.Context) error {
-	categoryID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid category ID")
+	var (
+		cat *domain.Category
+		err error
+	)
+	opts := readOptions(c)
+	if categoryID, parseErr := uuid.Parse(c.Param("id")); parseErr == nil {
+		cat, err = h.categoryUsecase.GetCategory(c.Request().Context(), categoryID, opts)
+	} else {
+		cat, err = h.categoryUsecase.GetCategoryBySlug(c.Request().Context(), c.Param("id"), opts)
 	}
-
-	cat, err := h.categoryUsecase.GetCategory(c.Request().Context(), categoryID)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, "Category not found")
+		return err
 	}
 
+	if appMiddleware.NotModified(c, cat.Version) {
+		return c.NoContent(http.StatusNotModified)
+	}
 	return c.JSON(http.StatusOK, cat)
 }
 
 func (h *CategoryHandler) GetAllCategories(c echo.Context) error {
-	categories, err := h.categoryUsecase.GetAllCategories(c.Request().Context())
+	categories, err := h.categoryUsecase.GetAllCategories(c.Request().Context(), readOptions(c))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "Failed to retrieve categories")
+		return err
 	}
 	return c.JSON(http.StatusOK, categories)
 }
 
+func (h *CategoryHandler) GetCategoryTree(c echo.Context) error {
+	tree, err := h.categoryUsecase.GetCategoryTree(c.Request().Context(), readOptions(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tree)
+}
+
 func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
 	categoryID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid category ID")
+		return apperror.Validation("invalid category ID")
 	}
 
-	title := c.FormValue("title")
-	description := c.FormValue("description")
+	var req UpdateCategoryRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
 
 	input := usecase.UpdateCategoryInput{
 		CategoryID:  categoryID,
-		Title:       title,
-		Description: description,
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Description: req.Description,
+		IsActive:    req.IsActive,
+		Version:     appMiddleware.IfMatch(c),
+	}
+
+	// parent_id is only changed when sent; an empty value moves the category
+	// to the top level.
+	if req.ParentID != nil {
+		if input.ParentID, err = optionalUUID(*req.ParentID); err != nil {
+			return apperror.Validation("invalid parent ID")
+		}
+		input.SetParent = true
 	}
 
 	fileHeader, err := c.FormFile("image")
 	if err == nil {
 		src, err := fileHeader.Open()
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, "Failed to open image file")
+			return err
 		}
 		defer src.Close()
 		input.ImageFile = src
@@ -114,24 +206,156 @@ func (h *CategoryHandler) UpdateCategory(c echo.Context) error {
 
 	updatedCategory, err := h.categoryUsecase.UpdateCategory(c.Request().Context(), input)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return c.JSON(http.StatusNotFound, err.Error())
-		}
-		return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Failed to update category: %v", err))
+		return err
 	}
 
+	appMiddleware.SetETag(c, updatedCategory.Version)
 	return c.JSON(http.StatusOK, updatedCategory)
 }
 
+// ReorderCategories sets the display order of several categories at once.
+func (h *CategoryHandler) ReorderCategories(c echo.Context) error {
+	var req reorderRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	order := make([]domain.CategoryOrder, len(req.Order))
+	for i, o := range req.Order {
+		order[i] = domain.CategoryOrder{CategoryID: o.ID, SortOrder: o.SortOrder}
+	}
+
+	if err := h.categoryUsecase.ReorderCategories(c.Request().Context(), order); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
 func (h *CategoryHandler) DeleteCategory(c echo.Context) error {
 	categoryID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid category ID")
+		return apperror.Validation("invalid category ID")
+	}
+
+	input := usecase.DeleteCategoryInput{CategoryID: categoryID, Version: appMiddleware.IfMatch(c)}
+	// reparent_children_to is a category ID, or "root" to move the children
+	// to the top level.
+	if target := c.QueryParam("reparent_children_to"); target != "" {
+		input.ReparentChildren = true
+		if target != "root" {
+			newParentID, err := uuid.Parse(target)
+			if err != nil {
+				return apperror.Validation("invalid reparent_children_to")
+			}
+			input.NewParentID = &newParentID
+		}
+	}
+
+	// Pages are moved to reassign_to, or lose the category with force=true.
+	if input.ReassignTo, err = optionalUUID(c.QueryParam("reassign_to")); err != nil {
+		return apperror.Validation("invalid reassign_to")
+	}
+	input.Force = c.QueryParam("force") == "true"
+
+	affected, err := h.categoryUsecase.DeleteCategory(c.Request().Context(), input)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, echo.Map{"affected_pages": affected})
+}
+
+func (h *CategoryHandler) GetDeletedCategories(c echo.Context) error {
+	categories, err := h.categoryUsecase.GetDeletedCategories(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, categories)
+}
+
+func (h *CategoryHandler) RestoreCategory(c echo.Context) error {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid category ID")
+	}
+
+	cat, err := h.categoryUsecase.RestoreCategory(c.Request().Context(), categoryID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, cat)
+}
+
+func (h *CategoryHandler) GetTranslations(c echo.Context) error {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid category ID")
+	}
+
+	translations, err := h.categoryUsecase.GetTranslations(c.Request().Context(), categoryID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, translations)
+}
+
+func (h *CategoryHandler) SetTranslation(c echo.Context) error {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid category ID")
+	}
+
+	var req TranslationRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 
-	if err := h.categoryUsecase.DeleteCategory(c.Request().Context(), categoryID); err != nil {
-		return c.JSON(http.StatusInternalServerError, "Failed to delete category")
+	input := usecase.TranslationInput{
+		CategoryID:  categoryID,
+		Locale:      c.Param("locale"),
+		Title:       req.Title,
+		Description: req.Description,
+	}
+	t, err := h.categoryUsecase.SetTranslation(c.Request().Context(), input)
+	if err != nil {
+		return err
 	}
+	return c.JSON(http.StatusOK, t)
+}
 
+func (h *CategoryHandler) DeleteTranslation(c echo.Context) error {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid category ID")
+	}
+
+	if err := h.categoryUsecase.DeleteTranslation(c.Request().Context(), categoryID, c.Param("locale")); err != nil {
+		return err
+	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+func optionalUUID(raw string) (*uuid.UUID, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// readOptions applies the negotiated locale and, for admins, the
+// include_inactive query parameter.
+func readOptions(c echo.Context) usecase.ReadOptions {
+	return usecase.ReadOptions{
+		IncludeInactive: c.QueryParam("include_inactive") == "true" && appMiddleware.IsAdmin(c),
+		Locale:          appMiddleware.Locale(c),
+	}
+}
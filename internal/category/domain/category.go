@@ -1,15 +1,54 @@
 package domain
 
 import (
+	"github.com/cavidyrm/instawall/internal/apperror"
 	"github.com/google/uuid"
 	"time"
 )
 
+var (
+	// ErrSlugTaken is returned when another category already uses a slug.
+	ErrSlugTaken = apperror.Conflict("slug_taken", "slug is already in use")
+	// ErrTitleTaken is returned when another category already uses a title.
+	ErrTitleTaken = apperror.Conflict("title_taken", "title is already in use")
+	// ErrVersionMismatch is returned when a conditional write expects a
+	// version of the category other than the current one.
+	ErrVersionMismatch = apperror.PreconditionFailed("version_mismatch", "category has been modified since it was read")
+	// ErrParentDeleted is returned when restoring a category whose parent
+	// is still deleted, which would hide it from the tree.
+	ErrParentDeleted = apperror.Conflict("parent_deleted", "parent category is deleted; restore it first")
+)
+
 // Category represents the core Category entity in the domain layer.
 type Category struct {
-	ID          uuid.UUID `db:"id"`
+	ID          uuid.UUID  `db:"id"`
+	ParentID    *uuid.UUID `db:"parent_id"`
+	Title       string     `db:"title"`
+	Slug        string     `db:"slug"`
+	Description string     `db:"description"`
+	ImageURL    string     `db:"image_url"`
+	SortOrder   int        `db:"sort_order"`
+	IsActive    bool       `db:"is_active"`
+	PageCount   int        `db:"page_count"` // listed pages directly in this category
+	CreatedAt   time.Time  `db:"created_at"`
+	DeletedAt   *time.Time `db:"deleted_at"`
+	Version     int64      `db:"version"` // bumped by the database whenever the category changes
+
+	Children []Category `db:"-"` // populated only when building the category tree
+}
+
+// CategoryOrder sets the display position of one category among its siblings.
+type CategoryOrder struct {
+	CategoryID uuid.UUID
+	SortOrder  int
+}
+
+// CategoryTranslation holds a category's title and description in a locale
+// other than the default one.
+type CategoryTranslation struct {
+	CategoryID  uuid.UUID `db:"category_id"`
+	Locale      string    `db:"locale"`
 	Title       string    `db:"title"`
 	Description string    `db:"description"`
-	ImageURL    string    `db:"image_url"`
-	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
 }
@@ -2,54 +2,324 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
 
 	"github.com/cavidyrm/instawall/internal/category/domain"
+	"github.com/cavidyrm/instawall/pkg/database"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // CategoryRepository provides a database implementation for category operations.
 type CategoryRepository struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	logger *slog.Logger
 }
 
-// NewCategoryRepository creates a new CategoryRepository.
-func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
-	return &CategoryRepository{db: db}
+// NewCategoryRepository creates a new CategoryRepository. A nil logger means
+// slog.Default().
+func NewCategoryRepository(db *sqlx.DB, logger *slog.Logger) *CategoryRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CategoryRepository{db: db, logger: logger}
 }
 
-// CreateCategory saves a new category to the database.
+// CreateCategory saves a new category to the database, placing it after its
+// existing siblings.
 func (r *CategoryRepository) CreateCategory(ctx context.Context, c *domain.Category) error {
-	query := `INSERT INTO categories (title, description, image_url)
-			  VALUES ($1, $2, $3) RETURNING id, created_at`
-	return r.db.QueryRowxContext(ctx, query, c.Title, c.Description, c.ImageURL).Scan(&c.ID, &c.CreatedAt)
+	query := `INSERT INTO categories (parent_id, title, slug, description, image_url, is_active, sort_order)
+			  VALUES ($1, $2, $3, $4, $5, $6,
+					  COALESCE((SELECT MAX(sort_order) FROM categories WHERE parent_id IS NOT DISTINCT FROM $1), 0) + 1)
+			  RETURNING id, sort_order, page_count, created_at, version`
+	return r.db.QueryRowxContext(ctx, query, c.ParentID, c.Title, c.Slug, c.Description, c.ImageURL, c.IsActive).
+		Scan(&c.ID, &c.SortOrder, &c.PageCount, &c.CreatedAt, &c.Version)
 }
 
 func (r *CategoryRepository) GetCategoryByID(ctx context.Context, categoryID uuid.UUID) (*domain.Category, error) {
 	var c domain.Category
-	query := `SELECT * FROM categories WHERE id = $1`
+	query := `SELECT * FROM categories WHERE id = $1 AND deleted_at IS NULL`
 	err := r.db.GetContext(ctx, &c, query, categoryID)
 	return &c, err
 }
 
-// GetAllCategories retrieves a list of all categories.
-func (r *CategoryRepository) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
+// GetDeletedCategoryByID retrieves a soft-deleted category that has not been
+// purged yet.
+func (r *CategoryRepository) GetDeletedCategoryByID(ctx context.Context, categoryID uuid.UUID) (*domain.Category, error) {
+	var c domain.Category
+	query := `SELECT * FROM categories WHERE id = $1 AND deleted_at IS NOT NULL`
+	err := r.db.GetContext(ctx, &c, query, categoryID)
+	return &c, err
+}
+
+// GetDeletedCategories retrieves soft-deleted categories, most recently
+// deleted first.
+func (r *CategoryRepository) GetDeletedCategories(ctx context.Context) ([]domain.Category, error) {
 	var categories []domain.Category
-	query := `SELECT * FROM categories ORDER BY title ASC`
+	query := `SELECT * FROM categories WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC`
 	err := r.db.SelectContext(ctx, &categories, query)
 	return categories, err
 }
 
-// UpdateCategory updates an existing category's details.
+// GetCategoryBySlug retrieves a category by its slug.
+func (r *CategoryRepository) GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error) {
+	var c domain.Category
+	query := `SELECT * FROM categories WHERE slug = $1 AND deleted_at IS NULL`
+	err := r.db.GetContext(ctx, &c, query, slug)
+	return &c, err
+}
+
+// SlugExists reports whether a category other than excludeID uses the slug.
+func (r *CategoryRepository) SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error) {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM categories WHERE slug = $1 AND id <> $2 AND deleted_at IS NULL)`
+	err := r.db.GetContext(ctx, &exists, query, slug, excludeID)
+	return exists, err
+}
+
+// GetAllCategories retrieves categories in display order, optionally
+// including inactive ones.
+func (r *CategoryRepository) GetAllCategories(ctx context.Context, includeInactive bool) ([]domain.Category, error) {
+	var categories []domain.Category
+	query := `SELECT * FROM categories WHERE deleted_at IS NULL AND ($1 OR is_active) ORDER BY sort_order ASC, title ASC`
+	err := r.db.SelectContext(ctx, &categories, query, includeInactive)
+	return categories, err
+}
+
+// ReorderCategories sets the sort order of several categories at once. It
+// returns sql.ErrNoRows, changing nothing, if any of the categories is missing.
+func (r *CategoryRepository) ReorderCategories(ctx context.Context, order []domain.CategoryOrder) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	stmt, err := tx.PreparexContext(ctx, `UPDATE categories SET sort_order = $1 WHERE id = $2 AND deleted_at IS NULL`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, o := range order {
+		res, err := stmt.ExecContext(ctx, o.SortOrder, o.CategoryID)
+		if err != nil {
+			return err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return err
+		} else if n == 0 {
+			return sql.ErrNoRows
+		}
+	}
+	return tx.Commit()
+}
+
+// IsDescendant reports whether categoryID is ancestorID itself or lies
+// anywhere below it in the hierarchy.
+func (r *CategoryRepository) IsDescendant(ctx context.Context, categoryID, ancestorID uuid.UUID) (bool, error) {
+	var found bool
+	query := `WITH RECURSIVE subtree AS (
+				  SELECT id FROM categories WHERE id = $1
+				  UNION
+				  SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+			  )
+			  SELECT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`
+	err := r.db.GetContext(ctx, &found, query, ancestorID, categoryID)
+	return found, err
+}
+
+// CountChildren returns the number of direct child categories.
+func (r *CategoryRepository) CountChildren(ctx context.Context, categoryID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.GetContext(ctx, &n, `SELECT COUNT(*) FROM categories WHERE parent_id = $1 AND deleted_at IS NULL`, categoryID)
+	return n, err
+}
+
+// CountPages returns the number of pages in a category.
+func (r *CategoryRepository) CountPages(ctx context.Context, categoryID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.GetContext(ctx, &n, `SELECT COUNT(*) FROM page_categories WHERE category_id = $1`, categoryID)
+	return n, err
+}
+
+// UpdateCategory updates an existing category's details. The update only
+// applies while the row is still at c.Version; otherwise it returns
+// domain.ErrVersionMismatch, or sql.ErrNoRows if the category is gone. On
+// success c.Version is the new version.
 func (r *CategoryRepository) UpdateCategory(ctx context.Context, c *domain.Category) error {
-	query := `UPDATE categories SET title = $1, description = $2, image_url = $3 WHERE id = $4`
-	_, err := r.db.ExecContext(ctx, query, c.Title, c.Description, c.ImageURL, c.ID)
+	query := `UPDATE categories SET parent_id = $1, title = $2, slug = $3, description = $4, image_url = $5, is_active = $6
+			  WHERE id = $7 AND deleted_at IS NULL AND version = $8
+			  RETURNING version`
+	err := r.db.QueryRowxContext(ctx, query, c.ParentID, c.Title, c.Slug, c.Description, c.ImageURL, c.IsActive, c.ID, c.Version).Scan(&c.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		var exists bool
+		if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1 AND deleted_at IS NULL)`, c.ID); err != nil {
+			return err
+		}
+		if !exists {
+			return sql.ErrNoRows
+		}
+		return domain.ErrVersionMismatch
+	}
 	return err
 }
 
-// DeleteCategory removes a category from the database.
-func (r *CategoryRepository) DeleteCategory(ctx context.Context, categoryID uuid.UUID) error {
-	query := `DELETE FROM categories WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, categoryID)
-	return err
+// SoftDeleteCategory marks a category as deleted in a single transaction.
+// Its children, including already deleted ones, are first moved under
+// newParentID (nil for the top level). With reassignTo set, its pages are
+// moved to that category; otherwise they keep the link, so that a restore
+// brings it back. With version set, the category is only deleted while it is
+// at that version. It returns the number of pages in the category.
+func (r *CategoryRepository) SoftDeleteCategory(ctx context.Context, categoryID uuid.UUID, version *int64, newParentID, reassignTo *uuid.UUID) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	var current int64
+	if err := tx.GetContext(ctx, &current, `SELECT version FROM categories WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, categoryID); err != nil {
+		return 0, err
+	}
+	if version != nil && *version != current {
+		return 0, domain.ErrVersionMismatch
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE categories SET parent_id = $1 WHERE parent_id = $2`, newParentID, categoryID); err != nil {
+		return 0, err
+	}
+
+	var affected int
+	if reassignTo != nil {
+		query := `INSERT INTO page_categories (page_id, category_id)
+				  SELECT page_id, $1 FROM page_categories WHERE category_id = $2
+				  ON CONFLICT DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, *reassignTo, categoryID); err != nil {
+			return 0, err
+		}
+		res, err := tx.ExecContext(ctx, `DELETE FROM page_categories WHERE category_id = $1`, categoryID)
+		if err != nil {
+			return 0, err
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		affected = int(n)
+	} else if err := tx.GetContext(ctx, &affected, `SELECT COUNT(*) FROM page_categories WHERE category_id = $1`, categoryID); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE categories SET deleted_at = NOW() WHERE id = $1`, categoryID); err != nil {
+		return 0, err
+	}
+	return affected, tx.Commit()
+}
+
+// RestoreCategory undeletes a category deleted after deletedAfter. It
+// returns sql.ErrNoRows if there is no such category, ErrParentDeleted if its
+// parent is deleted, and ErrSlugTaken or ErrTitleTaken if a live category has
+// claimed its slug or title meanwhile.
+func (r *CategoryRepository) RestoreCategory(ctx context.Context, categoryID uuid.UUID, deletedAfter time.Time) error {
+	query := `UPDATE categories c SET deleted_at = NULL
+			  WHERE c.id = $1 AND c.deleted_at > $2
+				AND NOT EXISTS (SELECT 1 FROM categories p WHERE p.id = c.parent_id AND p.deleted_at IS NOT NULL)`
+	res, err := r.db.ExecContext(ctx, query, categoryID, deletedAfter)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			if pqErr.Constraint == "idx_categories_title" {
+				return domain.ErrTitleTaken
+			}
+			return domain.ErrSlugTaken
+		}
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		var parentDeleted bool
+		query := `SELECT EXISTS (SELECT 1 FROM categories c JOIN categories p ON p.id = c.parent_id
+								 WHERE c.id = $1 AND p.deleted_at IS NOT NULL)`
+		if err := r.db.GetContext(ctx, &parentDeleted, query, categoryID); err != nil {
+			return err
+		}
+		if parentDeleted {
+			return domain.ErrParentDeleted
+		}
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// PurgeDeletedCategories permanently removes categories deleted before the
+// given time, together with their remaining page links.
+func (r *CategoryRepository) PurgeDeletedCategories(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM categories WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// UpsertTranslation creates or replaces a category's translation for a locale.
+func (r *CategoryRepository) UpsertTranslation(ctx context.Context, t *domain.CategoryTranslation) error {
+	query := `INSERT INTO category_translations (category_id, locale, title, description)
+			  VALUES ($1, $2, $3, $4)
+			  ON CONFLICT (category_id, locale) DO UPDATE
+			  SET title = EXCLUDED.title, description = EXCLUDED.description, updated_at = NOW()
+			  RETURNING updated_at`
+	return r.db.QueryRowxContext(ctx, query, t.CategoryID, t.Locale, t.Title, t.Description).Scan(&t.UpdatedAt)
+}
+
+// DeleteTranslation removes a category's translation for a locale.
+func (r *CategoryRepository) DeleteTranslation(ctx context.Context, categoryID uuid.UUID, locale string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM category_translations WHERE category_id = $1 AND locale = $2`, categoryID, locale)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetTranslations retrieves every translation of a category.
+func (r *CategoryRepository) GetTranslations(ctx context.Context, categoryID uuid.UUID) ([]domain.CategoryTranslation, error) {
+	var translations []domain.CategoryTranslation
+	query := `SELECT * FROM category_translations WHERE category_id = $1 ORDER BY locale`
+	err := r.db.SelectContext(ctx, &translations, query, categoryID)
+	return translations, err
+}
+
+// GetTranslationsForLocale retrieves the translations of the given categories
+// into one locale, keyed by category ID.
+func (r *CategoryRepository) GetTranslationsForLocale(ctx context.Context, categoryIDs []uuid.UUID, locale string) (map[uuid.UUID]domain.CategoryTranslation, error) {
+	byID := make(map[uuid.UUID]domain.CategoryTranslation)
+	if len(categoryIDs) == 0 {
+		return byID, nil
+	}
+	ids := make([]string, len(categoryIDs))
+	for i, id := range categoryIDs {
+		ids[i] = id.String()
+	}
+
+	var translations []domain.CategoryTranslation
+	query := `SELECT * FROM category_translations WHERE category_id = ANY($1::uuid[]) AND locale = $2`
+	if err := r.db.SelectContext(ctx, &translations, query, pq.Array(ids), locale); err != nil {
+		return nil, err
+	}
+	for _, t := range translations {
+		byID[t.CategoryID] = t
+	}
+	return byID, nil
 }
@@ -2,19 +2,44 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"github.com/cavidyrm/instawall/internal/apperror"
 	"github.com/cavidyrm/instawall/internal/category/domain"
+	"github.com/cavidyrm/instawall/pkg/i18n"
+	"github.com/cavidyrm/instawall/pkg/slug"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
 	"io"
+	"log/slog"
+	"strconv"
+	"time"
 )
 
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/internal/category/usecase")
+
 // --- Interface Definitions for Dependencies ---
 type CategoryRepository interface {
 	CreateCategory(ctx context.Context, c *domain.Category) error
 	GetCategoryByID(ctx context.Context, categoryID uuid.UUID) (*domain.Category, error)
-	GetAllCategories(ctx context.Context) ([]domain.Category, error)
+	GetCategoryBySlug(ctx context.Context, slug string) (*domain.Category, error)
+	SlugExists(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error)
+	GetAllCategories(ctx context.Context, includeInactive bool) ([]domain.Category, error)
+	ReorderCategories(ctx context.Context, order []domain.CategoryOrder) error
+	IsDescendant(ctx context.Context, categoryID, ancestorID uuid.UUID) (bool, error)
+	CountChildren(ctx context.Context, categoryID uuid.UUID) (int, error)
 	UpdateCategory(ctx context.Context, c *domain.Category) error
-	DeleteCategory(ctx context.Context, categoryID uuid.UUID) error
+	CountPages(ctx context.Context, categoryID uuid.UUID) (int, error)
+	SoftDeleteCategory(ctx context.Context, categoryID uuid.UUID, version *int64, newParentID, reassignTo *uuid.UUID) (int, error)
+	GetDeletedCategoryByID(ctx context.Context, categoryID uuid.UUID) (*domain.Category, error)
+	GetDeletedCategories(ctx context.Context) ([]domain.Category, error)
+	RestoreCategory(ctx context.Context, categoryID uuid.UUID, deletedAfter time.Time) error
+	PurgeDeletedCategories(ctx context.Context, before time.Time) (int64, error)
+	UpsertTranslation(ctx context.Context, t *domain.CategoryTranslation) error
+	DeleteTranslation(ctx context.Context, categoryID uuid.UUID, locale string) error
+	GetTranslations(ctx context.Context, categoryID uuid.UUID) ([]domain.CategoryTranslation, error)
+	GetTranslationsForLocale(ctx context.Context, categoryIDs []uuid.UUID, locale string) (map[uuid.UUID]domain.CategoryTranslation, error)
 }
 type FileStore interface {
 	UploadFile(ctx context.Context, file io.Reader, fileSize int64, originalFilename string) (string, error)
@@ -22,61 +47,222 @@ type FileStore interface {
 
 // --- Usecase Implementation ---
 type CategoryUsecase struct {
-	catRepo   CategoryRepository
-	fileStore FileStore
+	catRepo       CategoryRepository
+	fileStore     FileStore
+	restoreWindow time.Duration
+	logger        *slog.Logger
 }
 
-func NewCategoryUsecase(cr CategoryRepository, fs FileStore) *CategoryUsecase {
-	return &CategoryUsecase{catRepo: cr, fileStore: fs}
+// NewCategoryUsecase creates a CategoryUsecase. Deleted categories can be
+// restored for restoreWindow, after which they are purged. A nil logger
+// means slog.Default().
+func NewCategoryUsecase(cr CategoryRepository, fs FileStore, restoreWindow time.Duration, logger *slog.Logger) *CategoryUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &CategoryUsecase{catRepo: cr, fileStore: fs, restoreWindow: restoreWindow, logger: logger}
 }
 
 // --- Input DTOs ---
 type CreateCategoryInput struct {
+	ParentID    *uuid.UUID
 	Title       string
+	Slug        string // Optional, generated from Title when empty
 	Description string
+	IsActive    *bool // Optional, defaults to true
 	ImageFile   io.Reader
 	ImageSize   int64
 	ImageName   string
 }
 type UpdateCategoryInput struct {
 	CategoryID  uuid.UUID
-	Title       string
-	Description string
-	ImageFile   io.Reader // Optional
+	SetParent   bool       // Move the category under ParentID
+	ParentID    *uuid.UUID // nil moves it to the top level
+	Title       *string    // Optional: nil fields are left unchanged
+	Slug        string     // Optional, the existing slug is kept when empty
+	Description *string    // Optional
+	IsActive    *bool      // Optional
+	ImageFile   io.Reader  // Optional
 	ImageSize   int64
 	ImageName   string
+	Version     *int64 // Optional: the update fails unless the category is at this version
+}
+type DeleteCategoryInput struct {
+	CategoryID       uuid.UUID
+	Version          *int64     // Optional: the delete fails unless the category is at this version
+	ReparentChildren bool       // Move children instead of refusing to delete
+	NewParentID      *uuid.UUID // nil moves the children to the top level
+	ReassignTo       *uuid.UUID // Move the category's pages to this category
+	Force            bool       // Delete even though pages would lose the category
+}
+
+type TranslationInput struct {
+	CategoryID  uuid.UUID
+	Locale      string
+	Title       string
+	Description string
+}
+
+// ReadOptions control how categories are presented to the caller.
+type ReadOptions struct {
+	IncludeInactive bool   // admins only
+	Locale          string // translate into this locale; "" for the default
 }
 
 // --- Usecase Methods ---
 
+var errParentNotFound = apperror.Invalid("invalid_parent", "parent category not found")
+
 func (uc *CategoryUsecase) CreateCategory(ctx context.Context, input CreateCategoryInput) (*domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.CreateCategory")
+	defer span.End()
+
+	if input.ParentID != nil {
+		if _, err := uc.catRepo.GetCategoryByID(ctx, *input.ParentID); err != nil {
+			return nil, errParentNotFound
+		}
+	}
+	categorySlug, err := uc.uniqueSlug(ctx, input.Slug, input.Title, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
 	imageURL, err := uc.fileStore.UploadFile(ctx, input.ImageFile, input.ImageSize, input.ImageName)
 	if err != nil {
 		return nil, err
 	}
 	newCategory := &domain.Category{
+		ParentID:    input.ParentID,
 		Title:       input.Title,
+		Slug:        categorySlug,
 		Description: input.Description,
 		ImageURL:    imageURL,
+		IsActive:    input.IsActive == nil || *input.IsActive,
 	}
 	if err := uc.catRepo.CreateCategory(ctx, newCategory); err != nil {
-		return nil, err
+		return nil, apperror.FromDB(err, "category")
 	}
 	return newCategory, nil
 }
 
-func (uc *CategoryUsecase) GetCategory(ctx context.Context, categoryID uuid.UUID) (*domain.Category, error) {
-	return uc.catRepo.GetCategoryByID(ctx, categoryID)
+// GetCategory looks up a category by ID.
+func (uc *CategoryUsecase) GetCategory(ctx context.Context, categoryID uuid.UUID, opts ReadOptions) (*domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategory")
+	defer span.End()
+
+	c, err := uc.catRepo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "category")
+	}
+	return uc.present(ctx, c, opts)
+}
+
+// GetCategoryBySlug looks up a category by its URL slug.
+func (uc *CategoryUsecase) GetCategoryBySlug(ctx context.Context, categorySlug string, opts ReadOptions) (*domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoryBySlug")
+	defer span.End()
+
+	c, err := uc.catRepo.GetCategoryBySlug(ctx, categorySlug)
+	if err != nil {
+		return nil, apperror.FromDB(err, "category")
+	}
+	return uc.present(ctx, c, opts)
+}
+
+func (uc *CategoryUsecase) GetAllCategories(ctx context.Context, opts ReadOptions) ([]domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetAllCategories")
+	defer span.End()
+
+	categories, err := uc.catRepo.GetAllCategories(ctx, opts.IncludeInactive)
+	if err != nil {
+		return nil, err
+	}
+	return categories, uc.localize(ctx, categories, opts.Locale)
+}
+
+// ReorderCategories sets the display order of the given categories.
+func (uc *CategoryUsecase) ReorderCategories(ctx context.Context, order []domain.CategoryOrder) error {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.ReorderCategories")
+	defer span.End()
+
+	if len(order) == 0 {
+		return apperror.Invalid("invalid_order", "no categories given")
+	}
+	seen := make(map[uuid.UUID]bool, len(order))
+	for _, o := range order {
+		if seen[o.CategoryID] {
+			return apperror.Invalid("invalid_order", fmt.Sprintf("category %s listed more than once", o.CategoryID))
+		}
+		seen[o.CategoryID] = true
+	}
+	return apperror.FromDB(uc.catRepo.ReorderCategories(ctx, order), "category")
 }
 
-func (uc *CategoryUsecase) GetAllCategories(ctx context.Context) ([]domain.Category, error) {
-	return uc.catRepo.GetAllCategories(ctx)
+// GetCategoryTree returns the top-level categories with their descendants
+// nested under Children, in display order. Unless inactive categories are
+// included, they are left out together with everything below them.
+func (uc *CategoryUsecase) GetCategoryTree(ctx context.Context, opts ReadOptions) ([]domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetCategoryTree")
+	defer span.End()
+
+	categories, err := uc.GetAllCategories(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	children := make(map[uuid.UUID][]domain.Category)
+	var roots []domain.Category
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+	var attach func(nodes []domain.Category) []domain.Category
+	attach = func(nodes []domain.Category) []domain.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+	return attach(roots), nil
 }
 
 func (uc *CategoryUsecase) UpdateCategory(ctx context.Context, input UpdateCategoryInput) (*domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.UpdateCategory")
+	defer span.End()
+
 	existingCategory, err := uc.catRepo.GetCategoryByID(ctx, input.CategoryID)
 	if err != nil {
-		return nil, fmt.Errorf("category not found")
+		return nil, apperror.FromDB(err, "category")
+	}
+	if input.Version != nil && *input.Version != existingCategory.Version {
+		return nil, domain.ErrVersionMismatch
+	}
+
+	parentID := existingCategory.ParentID
+	if input.SetParent {
+		if input.ParentID != nil {
+			if _, err := uc.catRepo.GetCategoryByID(ctx, *input.ParentID); err != nil {
+				return nil, errParentNotFound
+			}
+			cycle, err := uc.catRepo.IsDescendant(ctx, *input.ParentID, input.CategoryID)
+			if err != nil {
+				return nil, err
+			}
+			if cycle {
+				return nil, apperror.Invalid("invalid_parent", "a category cannot be moved under itself or its descendants")
+			}
+		}
+		parentID = input.ParentID
+	}
+
+	// Slugs stay stable across title changes so existing links keep working.
+	categorySlug := existingCategory.Slug
+	if input.Slug != "" {
+		if categorySlug, err = uc.uniqueSlug(ctx, input.Slug, "", input.CategoryID); err != nil {
+			return nil, err
+		}
 	}
 
 	imageURL := existingCategory.ImageURL
@@ -88,19 +274,271 @@ func (uc *CategoryUsecase) UpdateCategory(ctx context.Context, input UpdateCateg
 		imageURL = newImageURL
 	}
 
+	isActive := existingCategory.IsActive
+	if input.IsActive != nil {
+		isActive = *input.IsActive
+	}
+
+	title, description := existingCategory.Title, existingCategory.Description
+	if input.Title != nil {
+		title = *input.Title
+	}
+	if input.Description != nil {
+		description = *input.Description
+	}
+
 	categoryToUpdate := &domain.Category{
 		ID:          input.CategoryID,
-		Title:       input.Title,
-		Description: input.Description,
+		ParentID:    parentID,
+		Title:       title,
+		Slug:        categorySlug,
+		Description: description,
 		ImageURL:    imageURL,
+		SortOrder:   existingCategory.SortOrder,
+		IsActive:    isActive,
+		PageCount:   existingCategory.PageCount,
+		CreatedAt:   existingCategory.CreatedAt,
+		Version:     existingCategory.Version,
 	}
 
 	if err := uc.catRepo.UpdateCategory(ctx, categoryToUpdate); err != nil {
-		return nil, err
+		return nil, apperror.FromDB(err, "category")
 	}
 	return categoryToUpdate, nil
 }
 
-func (uc *CategoryUsecase) DeleteCategory(ctx context.Context, categoryID uuid.UUID) error {
-	return uc.catRepo.DeleteCategory(ctx, categoryID)
+// DeleteCategory soft-deletes a category and returns the number of pages
+// that were in it. A category with children is only deleted when the caller
+// asks for them to be re-parented, and one with pages only when its pages are
+// reassigned or the caller forces it.
+func (uc *CategoryUsecase) DeleteCategory(ctx context.Context, input DeleteCategoryInput) (int, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.DeleteCategory")
+	defer span.End()
+
+	existing, err := uc.catRepo.GetCategoryByID(ctx, input.CategoryID)
+	if err != nil {
+		return 0, apperror.FromDB(err, "category")
+	}
+	if input.Version != nil && *input.Version != existing.Version {
+		return 0, domain.ErrVersionMismatch
+	}
+
+	if !input.ReparentChildren {
+		n, err := uc.catRepo.CountChildren(ctx, input.CategoryID)
+		if err != nil {
+			return 0, err
+		}
+		if n > 0 {
+			return 0, apperror.Conflict("category_has_children", fmt.Sprintf("category has %d child categories", n)).With("child_categories", n)
+		}
+	} else if input.NewParentID != nil {
+		if _, err := uc.catRepo.GetCategoryByID(ctx, *input.NewParentID); err != nil {
+			return 0, errParentNotFound
+		}
+		inside, err := uc.catRepo.IsDescendant(ctx, *input.NewParentID, input.CategoryID)
+		if err != nil {
+			return 0, err
+		}
+		if inside {
+			return 0, apperror.Invalid("invalid_parent", "children cannot be moved under the deleted category's subtree")
+		}
+	}
+
+	if input.ReassignTo != nil {
+		if *input.ReassignTo == input.CategoryID {
+			return 0, apperror.Invalid("invalid_reassignment", "pages cannot be reassigned to the deleted category")
+		}
+		if _, err := uc.catRepo.GetCategoryByID(ctx, *input.ReassignTo); err != nil {
+			return 0, apperror.Invalid("invalid_reassignment", "target category not found")
+		}
+	} else if !input.Force {
+		n, err := uc.catRepo.CountPages(ctx, input.CategoryID)
+		if err != nil {
+			return 0, err
+		}
+		if n > 0 {
+			return n, apperror.Conflict("category_has_pages", fmt.Sprintf("category has %d pages; reassign them or force the deletion", n)).With("affected_pages", n)
+		}
+	}
+
+	affected, err := uc.catRepo.SoftDeleteCategory(ctx, input.CategoryID, input.Version, input.NewParentID, input.ReassignTo)
+	if err != nil {
+		return 0, apperror.FromDB(err, "category")
+	}
+	uc.logger.InfoContext(ctx, "category deleted", "category_id", input.CategoryID, "affected_pages", affected)
+	return affected, nil
+}
+
+// GetDeletedCategories lists the deleted categories that have not been purged.
+func (uc *CategoryUsecase) GetDeletedCategories(ctx context.Context) ([]domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetDeletedCategories")
+	defer span.End()
+
+	return uc.catRepo.GetDeletedCategories(ctx)
+}
+
+// RestoreCategory undeletes a category deleted within the restore window.
+// A category under a deleted parent can only be restored after its parent.
+func (uc *CategoryUsecase) RestoreCategory(ctx context.Context, categoryID uuid.UUID) (*domain.Category, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.RestoreCategory")
+	defer span.End()
+
+	deleted, err := uc.catRepo.GetDeletedCategoryByID(ctx, categoryID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "deleted category")
+	}
+	if err := uc.catRepo.RestoreCategory(ctx, categoryID, time.Now().Add(-uc.restoreWindow)); err != nil {
+		if errors.Is(err, domain.ErrParentDeleted) && deleted.ParentID != nil {
+			return nil, domain.ErrParentDeleted.With("parent_id", *deleted.ParentID)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperror.Conflict("restore_window_passed",
+				fmt.Sprintf("category was deleted more than %s ago and can no longer be restored", uc.restoreWindow))
+		}
+		// ErrParentDeleted, ErrSlugTaken and ErrTitleTaken are already
+		// conflicts.
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "category restored", "category_id", categoryID)
+	return uc.catRepo.GetCategoryByID(ctx, categoryID)
+}
+
+// PurgeDeleted permanently removes categories whose restore window has
+// passed and returns how many were removed.
+func (uc *CategoryUsecase) PurgeDeleted(ctx context.Context) (int64, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.PurgeDeleted")
+	defer span.End()
+
+	return uc.catRepo.PurgeDeletedCategories(ctx, time.Now().Add(-uc.restoreWindow))
+}
+
+// SetTranslation creates or replaces a category's translation into a locale.
+func (uc *CategoryUsecase) SetTranslation(ctx context.Context, input TranslationInput) (*domain.CategoryTranslation, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.SetTranslation")
+	defer span.End()
+
+	locale, err := i18n.Normalize(input.Locale)
+	if err != nil {
+		return nil, apperror.Invalid("invalid_locale", err.Error())
+	}
+	if input.Title == "" {
+		return nil, apperror.Invalid("invalid_translation", "title is required")
+	}
+	if _, err := uc.catRepo.GetCategoryByID(ctx, input.CategoryID); err != nil {
+		return nil, apperror.FromDB(err, "category")
+	}
+	t := &domain.CategoryTranslation{
+		CategoryID:  input.CategoryID,
+		Locale:      locale,
+		Title:       input.Title,
+		Description: input.Description,
+	}
+	if err := uc.catRepo.UpsertTranslation(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetTranslations lists every translation of a category.
+func (uc *CategoryUsecase) GetTranslations(ctx context.Context, categoryID uuid.UUID) ([]domain.CategoryTranslation, error) {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.GetTranslations")
+	defer span.End()
+
+	if _, err := uc.catRepo.GetCategoryByID(ctx, categoryID); err != nil {
+		return nil, apperror.FromDB(err, "category")
+	}
+	return uc.catRepo.GetTranslations(ctx, categoryID)
+}
+
+// DeleteTranslation removes a category's translation into a locale.
+func (uc *CategoryUsecase) DeleteTranslation(ctx context.Context, categoryID uuid.UUID, locale string) error {
+	ctx, span := tracer.Start(ctx, "CategoryUsecase.DeleteTranslation")
+	defer span.End()
+
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return apperror.Invalid("invalid_locale", err.Error())
+	}
+	return apperror.FromDB(uc.catRepo.DeleteTranslation(ctx, categoryID, locale), "translation")
+}
+
+// uniqueSlug normalizes an explicitly requested slug, which must be free, or
+// derives one from title and appends a numeric suffix until no other
+// category uses it.
+func (uc *CategoryUsecase) uniqueSlug(ctx context.Context, requested, title string, excludeID uuid.UUID) (string, error) {
+	if requested != "" {
+		s := slug.Make(requested)
+		if s == "" {
+			return "", apperror.Invalid("invalid_slug", "slug must contain letters or digits")
+		}
+		taken, err := uc.catRepo.SlugExists(ctx, s, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", domain.ErrSlugTaken.With("slug", s)
+		}
+		return s, nil
+	}
+
+	base := slug.Make(title)
+	if base == "" {
+		base = "category"
+	}
+
+	candidate := base
+	for n := 2; ; n++ {
+		taken, err := uc.catRepo.SlugExists(ctx, candidate, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		suffix := "-" + strconv.Itoa(n)
+		candidate = base[:min(len(base), slug.MaxLength-len(suffix))] + suffix
+	}
+}
+
+// present hides an inactive category unless asked to include it, and
+// translates it into the requested locale.
+func (uc *CategoryUsecase) present(ctx context.Context, c *domain.Category, opts ReadOptions) (*domain.Category, error) {
+	if !c.IsActive && !opts.IncludeInactive {
+		return nil, apperror.NotFound("category")
+	}
+	categories := []domain.Category{*c}
+	if err := uc.localize(ctx, categories, opts.Locale); err != nil {
+		return nil, err
+	}
+	return &categories[0], nil
+}
+
+// localize replaces titles and descriptions with their translations into
+// locale where one exists. Categories without a translation, or fields left
+// empty in it, keep the default-locale text.
+func (uc *CategoryUsecase) localize(ctx context.Context, categories []domain.Category, locale string) error {
+	if locale == "" || len(categories) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(categories))
+	for i, c := range categories {
+		ids[i] = c.ID
+	}
+	translations, err := uc.catRepo.GetTranslationsForLocale(ctx, ids, locale)
+	if err != nil {
+		return err
+	}
+	for i := range categories {
+		t, ok := translations[categories[i].ID]
+		if !ok {
+			continue
+		}
+		if t.Title != "" {
+			categories[i].Title = t.Title
+		}
+		if t.Description != "" {
+			categories[i].Description = t.Description
+		}
+	}
+	return nil
 }
@@ -0,0 +1,50 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// CategoryPurger permanently removes deleted categories past their restore window.
+type CategoryPurger interface {
+	PurgeDeleted(ctx context.Context) (int64, error)
+}
+
+// Purger periodically purges deleted categories.
+type Purger struct {
+	categories CategoryPurger
+	interval   time.Duration
+	logger     *slog.Logger
+}
+
+// NewPurger creates a new Purger. A nil logger means slog.Default().
+func NewPurger(c CategoryPurger, interval time.Duration, logger *slog.Logger) *Purger {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Purger{categories: c, interval: interval, logger: logger.With("worker", "category_purger")}
+}
+
+// Run purges immediately and then every interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.categories.PurgeDeleted(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.logger.ErrorContext(ctx, "purge deleted categories", "err", err)
+		} else if n > 0 {
+			p.logger.InfoContext(ctx, "purged deleted categories", "count", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/feed/usecase"
+	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type FeedHandler struct {
+	feedUsecase *usecase.FeedUsecase
+}
+
+func RegisterFeedHandlers(e *echo.Echo, uc *usecase.FeedUsecase) {
+	h := &FeedHandler{feedUsecase: uc}
+
+	// Public feeds; a token is optional and personalizes is_favorited
+	pageGroup := e.Group("/pages")
+	pageGroup.GET("/trending", h.GetTrending, appMiddleware.OptionalJWTAuthMiddleware)
+	pageGroup.GET("/featured", h.GetFeatured, appMiddleware.OptionalJWTAuthMiddleware)
+
+	// Admin-only curation of the featured feed
+	adminGroup := e.Group("/admin/featured")
+	adminGroup.Use(appMiddleware.JWTAuthMiddleware, appMiddleware.AdminOnlyMiddleware)
+	adminGroup.GET("", h.GetAllFeatured)
+	adminGroup.POST("", h.CreateFeatured)
+	adminGroup.PUT("/:id", h.UpdateFeatured)
+	adminGroup.DELETE("/:id", h.DeleteFeatured)
+}
+
+// Request/Response Structs
+type CreateFeaturedRequest struct {
+	PageID   string     `json:"page_id" validate:"required,uuid"`
+	Position int        `json:"position" validate:"gte=0"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+type FeaturedRequest struct {
+	Position int        `json:"position" validate:"gte=0"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+// --- Handler Methods ---
+
+func (h *FeedHandler) GetTrending(c echo.Context) error {
+	return h.getFeed(c, usecase.FeedTrending)
+}
+
+func (h *FeedHandler) GetFeatured(c echo.Context) error {
+	return h.getFeed(c, usecase.FeedFeatured)
+}
+
+func (h *FeedHandler) getFeed(c echo.Context, feed string) error {
+	var categoryID *uuid.UUID
+	if s := c.QueryParam("category_id"); s != "" {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return apperror.Validation("invalid category ID")
+		}
+		categoryID = &id
+	}
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 10 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	pages, total, err := h.feedUsecase.GetFeed(c.Request().Context(), feed, categoryID, appMiddleware.ViewerID(c), limit, offset, appMiddleware.Locale(c))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(total))
+	return c.JSON(http.StatusOK, pages)
+}
+
+func (h *FeedHandler) GetAllFeatured(c echo.Context) error {
+	entries, err := h.feedUsecase.GetAllFeatured(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entries)
+}
+
+func (h *FeedHandler) CreateFeatured(c echo.Context) error {
+	adminID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	var req CreateFeaturedRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	pageID, err := uuid.Parse(req.PageID)
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	entry, err := h.feedUsecase.CreateFeatured(c.Request().Context(), usecase.FeaturedInput{
+		PageID:    pageID,
+		Position:  req.Position,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+		CreatedBy: adminID,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, entry)
+}
+
+func (h *FeedHandler) UpdateFeatured(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid featured entry ID")
+	}
+	var req FeaturedRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	entry, err := h.feedUsecase.UpdateFeatured(c.Request().Context(), id, usecase.FeaturedInput{
+		Position: req.Position,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, entry)
+}
+
+func (h *FeedHandler) DeleteFeatured(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid featured entry ID")
+	}
+	if err := h.feedUsecase.DeleteFeatured(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
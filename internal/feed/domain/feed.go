@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeaturedPage is an admin-curated entry of the featured feed.
+type FeaturedPage struct {
+	ID        uuid.UUID  `db:"id"`
+	PageID    uuid.UUID  `db:"page_id"`
+	Position  int        `db:"position"`
+	StartsAt  *time.Time `db:"starts_at"`
+	EndsAt    *time.Time `db:"ends_at"`
+	CreatedBy *uuid.UUID `db:"created_by"`
+	CreatedAt time.Time  `db:"created_at"`
+}
+
+// RankedPage is a page's position in a precomputed feed. Higher scores rank first.
+type RankedPage struct {
+	PageID      uuid.UUID
+	Score       float64
+	CategoryIDs []uuid.UUID
+}
+
+// TrendingWeights tunes the trending score of a page.
+type TrendingWeights struct {
+	View     float64
+	Click    float64
+	Favorite float64
+	HalfLife time.Duration // age at which an event counts half as much
+	Window   time.Duration // events older than this are ignored
+	MaxPages int
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"math"
+
+	"github.com/cavidyrm/instawall/internal/feed/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// FeedRepository provides a database implementation for feed ranking and
+// featured page curation.
+type FeedRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewFeedRepository creates a new FeedRepository. A nil logger means
+// slog.Default().
+func NewFeedRepository(db *sqlx.DB, logger *slog.Logger) *FeedRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FeedRepository{db: db, logger: logger}
+}
+
+// rankedRow is the scan target for ranking queries.
+type rankedRow struct {
+	PageID      uuid.UUID      `db:"page_id"`
+	Score       float64        `db:"score"`
+	CategoryIDs pq.StringArray `db:"category_ids"`
+}
+
+func (r rankedRow) toDomain() domain.RankedPage {
+	rp := domain.RankedPage{PageID: r.PageID, Score: r.Score}
+	for _, s := range r.CategoryIDs {
+		if id, err := uuid.Parse(s); err == nil {
+			rp.CategoryIDs = append(rp.CategoryIDs, id)
+		}
+	}
+	return rp
+}
+
+// ComputeTrendingScores scores pages by their recent views, clicks and
+// favorites, each decayed exponentially by age.
+func (r *FeedRepository) ComputeTrendingScores(ctx context.Context, w domain.TrendingWeights) ([]domain.RankedPage, error) {
+	halfLifeDays := w.HalfLife.Hours() / 24
+	if halfLifeDays <= 0 {
+		halfLifeDays = 1
+	}
+	lambda := math.Ln2 / halfLifeDays
+	windowDays := int(w.Window.Hours() / 24)
+
+	query := `WITH activity AS (
+				  SELECT page_id, SUM(($1::float8 * views + $2::float8 * clicks) * EXP(-$4::float8 * (CURRENT_DATE - day))) AS score
+				  FROM page_daily_stats
+				  WHERE day > CURRENT_DATE - $5::int
+				  GROUP BY page_id
+			  ), favorites AS (
+				  SELECT page_id, SUM($3::float8 * EXP(-$4::float8 * EXTRACT(EPOCH FROM NOW() - created_at) / 86400)) AS score
+				  FROM page_favorites
+				  WHERE created_at > NOW() - make_interval(days => $5::int)
+				  GROUP BY page_id
+			  )
+			  SELECT p.id AS page_id,
+					 COALESCE(a.score, 0) + COALESCE(f.score, 0) AS score,
+					 ARRAY(SELECT pc.category_id::text FROM page_categories pc WHERE pc.page_id = p.id) AS category_ids
+			  FROM pages p
+			  LEFT JOIN activity a ON a.page_id = p.id
+			  LEFT JOIN favorites f ON f.page_id = p.id
+			  WHERE NOT p.has_issue AND p.status = 'published' AND COALESCE(a.score, 0) + COALESCE(f.score, 0) > 0
+			  ORDER BY score DESC
+			  LIMIT $6`
+	var rows []rankedRow
+	if err := r.db.SelectContext(ctx, &rows, query, w.View, w.Click, w.Favorite, lambda, windowDays, w.MaxPages); err != nil {
+		return nil, err
+	}
+	ranked := make([]domain.RankedPage, len(rows))
+	for i, row := range rows {
+		ranked[i] = row.toDomain()
+	}
+	return ranked, nil
+}
+
+// GetActiveFeatured retrieves the currently active featured entries in
+// display order, skipping pages that are unpublished or have issues. The
+// score of each entry is its negated position so that higher scores rank
+// first, as in every other feed.
+func (r *FeedRepository) GetActiveFeatured(ctx context.Context) ([]domain.RankedPage, error) {
+	query := `SELECT DISTINCT ON (fp.page_id) fp.page_id,
+					 -fp.position::float8 AS score,
+					 ARRAY(SELECT pc.category_id::text FROM page_categories pc WHERE pc.page_id = fp.page_id) AS category_ids
+			  FROM featured_pages fp
+			  JOIN pages p ON p.id = fp.page_id
+			  WHERE (fp.starts_at IS NULL OR fp.starts_at <= NOW()) AND (fp.ends_at IS NULL OR fp.ends_at > NOW())
+				AND NOT p.has_issue AND p.status = 'published'
+			  ORDER BY fp.page_id, fp.position ASC`
+	var rows []rankedRow
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+	ranked := make([]domain.RankedPage, len(rows))
+	for i, row := range rows {
+		ranked[i] = row.toDomain()
+	}
+	return ranked, nil
+}
+
+// CreateFeatured saves a new featured entry.
+func (r *FeedRepository) CreateFeatured(ctx context.Context, f *domain.FeaturedPage) error {
+	query := `INSERT INTO featured_pages (page_id, position, starts_at, ends_at, created_by)
+			  VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return r.db.QueryRowxContext(ctx, query, f.PageID, f.Position, f.StartsAt, f.EndsAt, f.CreatedBy).Scan(&f.ID, &f.CreatedAt)
+}
+
+// GetFeaturedByID retrieves a single featured entry.
+func (r *FeedRepository) GetFeaturedByID(ctx context.Context, id uuid.UUID) (*domain.FeaturedPage, error) {
+	var f domain.FeaturedPage
+	err := r.db.GetContext(ctx, &f, `SELECT * FROM featured_pages WHERE id = $1`, id)
+	return &f, err
+}
+
+// GetAllFeatured retrieves every featured entry, including scheduled and
+// expired ones, in display order.
+func (r *FeedRepository) GetAllFeatured(ctx context.Context) ([]domain.FeaturedPage, error) {
+	var entries []domain.FeaturedPage
+	err := r.db.SelectContext(ctx, &entries, `SELECT * FROM featured_pages ORDER BY position ASC, created_at ASC`)
+	return entries, err
+}
+
+// UpdateFeatured updates the position and schedule of a featured entry.
+func (r *FeedRepository) UpdateFeatured(ctx context.Context, f *domain.FeaturedPage) error {
+	query := `UPDATE featured_pages SET position = $1, starts_at = $2, ends_at = $3 WHERE id = $4`
+	res, err := r.db.ExecContext(ctx, query, f.Position, f.StartsAt, f.EndsAt, f.ID)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// DeleteFeatured removes a featured entry.
+func (r *FeedRepository) DeleteFeatured(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM featured_pages WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+func requireRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
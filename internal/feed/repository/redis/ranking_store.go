@@ -0,0 +1,72 @@
+package redis
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/feed/domain"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// RankingStore keeps precomputed feeds as Redis sorted sets.
+type RankingStore struct {
+	rdb    *redis.Client
+	logger *slog.Logger
+}
+
+// NewRankingStore creates a new RankingStore. A nil logger means
+// slog.Default().
+func NewRankingStore(rdb *redis.Client, logger *slog.Logger) *RankingStore {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RankingStore{rdb: rdb, logger: logger}
+}
+
+// Replace atomically swaps the contents of the feed at key. Keys expire
+// after ttl so feeds that are no longer recomputed (e.g. for a category that
+// lost all its pages) disappear on their own.
+func (s *RankingStore) Replace(ctx context.Context, key string, pages []domain.RankedPage, ttl time.Duration) error {
+	if len(pages) == 0 {
+		return s.rdb.Del(ctx, key).Err()
+	}
+	members := make([]redis.Z, len(pages))
+	for i, p := range pages {
+		members[i] = redis.Z{Score: p.Score, Member: p.PageID.String()}
+	}
+
+	tmp := key + ":tmp"
+	pipe := s.rdb.TxPipeline()
+	pipe.Del(ctx, tmp)
+	pipe.ZAdd(ctx, tmp, members...)
+	pipe.Rename(ctx, tmp, key)
+	if ttl > 0 {
+		pipe.Expire(ctx, key, ttl)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// Range returns page IDs from the feed at key, highest score first, and the
+// total number of entries.
+func (s *RankingStore) Range(ctx context.Context, key string, offset, limit int) ([]uuid.UUID, int, error) {
+	pipe := s.rdb.Pipeline()
+	members := pipe.ZRevRange(ctx, key, int64(offset), int64(offset+limit-1))
+	total := pipe.ZCard(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, 0, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(members.Val()))
+	for _, m := range members.Val() {
+		id, err := uuid.Parse(m)
+		if err != nil {
+			s.logger.WarnContext(ctx, "skip malformed feed entry", "key", key, "member", m)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, int(total.Val()), nil
+}
@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/feed/domain"
+	pageDomain "github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/internal/feed/usecase")
+
+// Feed names.
+const (
+	FeedTrending = "trending"
+	FeedFeatured = "featured"
+)
+
+// --- Interface Definitions for Dependencies ---
+type FeedRepository interface {
+	ComputeTrendingScores(ctx context.Context, w domain.TrendingWeights) ([]domain.RankedPage, error)
+	GetActiveFeatured(ctx context.Context) ([]domain.RankedPage, error)
+	CreateFeatured(ctx context.Context, f *domain.FeaturedPage) error
+	GetFeaturedByID(ctx context.Context, id uuid.UUID) (*domain.FeaturedPage, error)
+	GetAllFeatured(ctx context.Context) ([]domain.FeaturedPage, error)
+	UpdateFeatured(ctx context.Context, f *domain.FeaturedPage) error
+	DeleteFeatured(ctx context.Context, id uuid.UUID) error
+}
+type RankingStore interface {
+	Replace(ctx context.Context, key string, pages []domain.RankedPage, ttl time.Duration) error
+	Range(ctx context.Context, key string, offset, limit int) ([]uuid.UUID, int, error)
+}
+type PageLister interface {
+	GetPageByID(ctx context.Context, pageID uuid.UUID) (*pageDomain.Page, error)
+	GetPagesByIDs(ctx context.Context, ids []uuid.UUID, viewerID uuid.UUID) ([]pageDomain.Page, error)
+	GetTranslationsForLocale(ctx context.Context, pageIDs []uuid.UUID, locale string) (map[uuid.UUID]pageDomain.PageTranslation, error)
+}
+
+// --- Usecase Implementation ---
+type FeedUsecase struct {
+	feedRepo FeedRepository
+	store    RankingStore
+	pages    PageLister
+	weights  domain.TrendingWeights
+	ttl      time.Duration
+	logger   *slog.Logger
+}
+
+// NewFeedUsecase creates a FeedUsecase. Precomputed feeds expire after ttl
+// unless recomputed. A nil logger means slog.Default().
+func NewFeedUsecase(fr FeedRepository, rs RankingStore, pl PageLister, w domain.TrendingWeights, ttl time.Duration, logger *slog.Logger) *FeedUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FeedUsecase{feedRepo: fr, store: rs, pages: pl, weights: w, ttl: ttl, logger: logger}
+}
+
+// --- Input DTOs ---
+type FeaturedInput struct {
+	PageID    uuid.UUID
+	Position  int
+	StartsAt  *time.Time
+	EndsAt    *time.Time
+	CreatedBy uuid.UUID
+}
+
+// --- Usecase Methods ---
+
+// GetFeed returns a page of a precomputed feed, optionally restricted to a
+// category and translated into locale, and the total number of pages in it.
+func (uc *FeedUsecase) GetFeed(ctx context.Context, feed string, categoryID *uuid.UUID, viewerID uuid.UUID, limit, offset int, locale string) ([]pageDomain.Page, int, error) {
+	ctx, span := tracer.Start(ctx, "FeedUsecase.GetFeed")
+	defer span.End()
+
+	ids, total, err := uc.store.Range(ctx, feedKey(feed, categoryID), offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	pages, err := uc.pages.GetPagesByIDs(ctx, ids, viewerID)
+	if err != nil || locale == "" {
+		return pages, total, err
+	}
+	translations, err := uc.pages.GetTranslationsForLocale(ctx, ids, locale)
+	if err != nil {
+		return nil, 0, err
+	}
+	pageDomain.Localize(pages, translations)
+	return pages, total, nil
+}
+
+// Recompute rebuilds every feed and its per-category variants.
+func (uc *FeedUsecase) Recompute(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "FeedUsecase.Recompute")
+	defer span.End()
+
+	trending, err := uc.feedRepo.ComputeTrendingScores(ctx, uc.weights)
+	if err != nil {
+		return fmt.Errorf("compute trending: %w", err)
+	}
+	if err := uc.publish(ctx, FeedTrending, trending); err != nil {
+		return err
+	}
+	return uc.RecomputeFeatured(ctx)
+}
+
+// RecomputeFeatured rebuilds only the featured feeds, e.g. after an admin edit.
+func (uc *FeedUsecase) RecomputeFeatured(ctx context.Context) error {
+	ctx, span := tracer.Start(ctx, "FeedUsecase.RecomputeFeatured")
+	defer span.End()
+
+	featured, err := uc.feedRepo.GetActiveFeatured(ctx)
+	if err != nil {
+		return fmt.Errorf("load featured: %w", err)
+	}
+	return uc.publish(ctx, FeedFeatured, featured)
+}
+
+// publish stores a ranking under the feed's global key and one key per category.
+func (uc *FeedUsecase) publish(ctx context.Context, feed string, ranked []domain.RankedPage) error {
+	byCategory := make(map[uuid.UUID][]domain.RankedPage)
+	for _, rp := range ranked {
+		for _, catID := range rp.CategoryIDs {
+			byCategory[catID] = append(byCategory[catID], rp)
+		}
+	}
+
+	if err := uc.store.Replace(ctx, feedKey(feed, nil), ranked, uc.ttl); err != nil {
+		return fmt.Errorf("store %s feed: %w", feed, err)
+	}
+	for catID, pages := range byCategory {
+		if err := uc.store.Replace(ctx, feedKey(feed, &catID), pages, uc.ttl); err != nil {
+			return fmt.Errorf("store %s feed for category %s: %w", feed, catID, err)
+		}
+	}
+	return nil
+}
+
+func (uc *FeedUsecase) CreateFeatured(ctx context.Context, input FeaturedInput) (*domain.FeaturedPage, error) {
+	ctx, span := tracer.Start(ctx, "FeedUsecase.CreateFeatured")
+	defer span.End()
+
+	if err := validateWindow(input.StartsAt, input.EndsAt); err != nil {
+		return nil, err
+	}
+	if _, err := uc.pages.GetPageByID(ctx, input.PageID); err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	f := &domain.FeaturedPage{
+		PageID:    input.PageID,
+		Position:  input.Position,
+		StartsAt:  input.StartsAt,
+		EndsAt:    input.EndsAt,
+		CreatedBy: &input.CreatedBy,
+	}
+	if err := uc.feedRepo.CreateFeatured(ctx, f); err != nil {
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "featured entry created", "featured_id", f.ID, "page_id", f.PageID)
+	uc.refreshFeatured(ctx)
+	return f, nil
+}
+
+func (uc *FeedUsecase) GetAllFeatured(ctx context.Context) ([]domain.FeaturedPage, error) {
+	ctx, span := tracer.Start(ctx, "FeedUsecase.GetAllFeatured")
+	defer span.End()
+
+	return uc.feedRepo.GetAllFeatured(ctx)
+}
+
+func (uc *FeedUsecase) UpdateFeatured(ctx context.Context, id uuid.UUID, input FeaturedInput) (*domain.FeaturedPage, error) {
+	ctx, span := tracer.Start(ctx, "FeedUsecase.UpdateFeatured")
+	defer span.End()
+
+	if err := validateWindow(input.StartsAt, input.EndsAt); err != nil {
+		return nil, err
+	}
+	f, err := uc.feedRepo.GetFeaturedByID(ctx, id)
+	if err != nil {
+		return nil, apperror.FromDB(err, "featured entry")
+	}
+	f.Position = input.Position
+	f.StartsAt = input.StartsAt
+	f.EndsAt = input.EndsAt
+	if err := uc.feedRepo.UpdateFeatured(ctx, f); err != nil {
+		return nil, err
+	}
+	uc.refreshFeatured(ctx)
+	return f, nil
+}
+
+func (uc *FeedUsecase) DeleteFeatured(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "FeedUsecase.DeleteFeatured")
+	defer span.End()
+
+	if err := uc.feedRepo.DeleteFeatured(ctx, id); err != nil {
+		return apperror.FromDB(err, "featured entry")
+	}
+	uc.logger.InfoContext(ctx, "featured entry deleted", "featured_id", id)
+	uc.refreshFeatured(ctx)
+	return nil
+}
+
+// refreshFeatured rebuilds the featured feeds after an admin edit. The edit
+// is already stored, so a failure is only logged; the ranker rebuilds the
+// feeds on its next run.
+func (uc *FeedUsecase) refreshFeatured(ctx context.Context) {
+	if err := uc.RecomputeFeatured(ctx); err != nil {
+		uc.logger.WarnContext(ctx, "recompute featured feeds", "err", err)
+	}
+}
+
+func validateWindow(start, end *time.Time) error {
+	if start != nil && end != nil && !end.After(*start) {
+		return apperror.Invalid("invalid_schedule", "ends_at must be after starts_at")
+	}
+	return nil
+}
+
+func feedKey(feed string, categoryID *uuid.UUID) string {
+	if categoryID == nil {
+		return "feed:" + feed
+	}
+	return "feed:" + feed + ":category:" + categoryID.String()
+}
@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// FeedRecomputer rebuilds the precomputed feeds.
+type FeedRecomputer interface {
+	Recompute(ctx context.Context) error
+}
+
+// Ranker periodically recomputes the trending and featured feeds.
+type Ranker struct {
+	feeds    FeedRecomputer
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewRanker creates a new Ranker. A nil logger means slog.Default().
+func NewRanker(f FeedRecomputer, interval time.Duration, logger *slog.Logger) *Ranker {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Ranker{feeds: f, interval: interval, logger: logger.With("worker", "feed_ranker")}
+}
+
+// Run recomputes the feeds immediately and then every interval until ctx is cancelled.
+func (r *Ranker) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.feeds.Recompute(ctx); err != nil && ctx.Err() == nil {
+			r.logger.ErrorContext(ctx, "recompute feeds", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package health serves the liveness and readiness endpoints the
+// orchestrator probes.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/logging"
+	"github.com/labstack/echo/v4"
+)
+
+// Check reports whether a dependency can be used.
+type Check func(ctx context.Context) error
+
+// Checker runs the readiness checks of the service's dependencies.
+type Checker struct {
+	timeout      time.Duration
+	names        []string
+	checks       []Check
+	shuttingDown atomic.Bool
+}
+
+// NewChecker creates a Checker that gives each check timeout to complete.
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add registers a readiness check. Checks must be added before the
+// handlers serve requests.
+func (c *Checker) Add(name string, check Check) {
+	c.names = append(c.names, name)
+	c.checks = append(c.checks, check)
+}
+
+// ShuttingDown makes readiness fail from now on, so that no new traffic is
+// routed to the service while it drains.
+func (c *Checker) ShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready runs every check concurrently and returns the result of each, as
+// "ok" or "unavailable", and whether all of them passed.
+func (c *Checker) Ready(ctx context.Context) (map[string]string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	errs := make([]error, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = check(ctx)
+		}()
+	}
+	wg.Wait()
+
+	results := make(map[string]string, len(c.checks))
+	ready := true
+	for i, err := range errs {
+		results[c.names[i]] = "ok"
+		if err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "readiness check failed", "check", c.names[i], "err", err)
+			results[c.names[i]] = "unavailable"
+			ready = false
+		}
+	}
+	return results, ready
+}
+
+// RegisterHandlers serves /healthz, which succeeds as long as the process
+// can handle requests, and /readyz, which also checks the dependencies.
+func RegisterHandlers(e *echo.Echo, c *Checker) {
+	e.GET("/healthz", func(ctx echo.Context) error {
+		return ctx.JSON(http.StatusOK, echo.Map{"status": "ok"})
+	})
+	e.GET("/readyz", c.readiness)
+}
+
+func (c *Checker) readiness(ctx echo.Context) error {
+	if c.shuttingDown.Load() {
+		return ctx.JSON(http.StatusServiceUnavailable, echo.Map{"status": "shutting_down"})
+	}
+	results, ready := c.Ready(ctx.Request().Context())
+	if !ready {
+		return ctx.JSON(http.StatusServiceUnavailable, echo.Map{"status": "unavailable", "checks": results})
+	}
+	return ctx.JSON(http.StatusOK, echo.Map{"status": "ok", "checks": results})
+}
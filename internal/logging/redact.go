@@ -0,0 +1,82 @@
+package logging
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[redacted]"
+
+// Attributes whose key contains one of these are always redacted; one-time
+// passwords are logged under otp or otp_code.
+var secretKeys = []string{"password", "token", "secret", "otp", "authorization", "cookie"}
+
+var (
+	// mobilePattern matches Iranian mobile numbers and E.164 numbers in
+	// free text, the formats the API accepts.
+	mobilePattern = regexp.MustCompile(`(?:\b0098|\b0|\b)9\d{9}\b|\+[1-9]\d{7,14}\b`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	bearerPattern = regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9._~+/=-]+`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]*\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// redact is the ReplaceAttr hook of every handler. Secrets are dropped by
+// key; mobile numbers, emails and tokens are masked wherever they appear in
+// a string, including the message and errors.
+func redact(_ []string, a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	if containsAny(key, secretKeys) {
+		return slog.String(a.Key, redacted)
+	}
+
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Scrub(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, Scrub(err.Error()))
+		}
+		if s, ok := v.Any().(interface{ String() string }); ok {
+			return slog.String(a.Key, Scrub(s.String()))
+		}
+	}
+	return a
+}
+
+// Scrub masks mobile numbers, email addresses and bearer tokens in s.
+func Scrub(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "Bearer "+redacted)
+	s = jwtPattern.ReplaceAllString(s, redacted)
+	s = emailPattern.ReplaceAllStringFunc(s, MaskEmail)
+	return mobilePattern.ReplaceAllStringFunc(s, MaskMobile)
+}
+
+// MaskMobile keeps only the last four digits of a phone number, e.g.
+// 09121234567 becomes *******4567.
+func MaskMobile(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return strings.Repeat("*", len(s)-4) + s[len(s)-4:]
+}
+
+// MaskEmail keeps the first character of the local part and the domain,
+// e.g. jane@example.com becomes j***@example.com.
+func MaskEmail(s string) string {
+	local, domain, ok := strings.Cut(s, "@")
+	if !ok || local == "" {
+		return redacted
+	}
+	return local[:1] + "***@" + domain
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
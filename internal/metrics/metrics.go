@@ -0,0 +1,116 @@
+// Package metrics defines the service's Prometheus metrics. They are
+// registered on Registry, which the admin server exposes at /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "instawall"
+
+// Registry holds every metric of the service, plus the Go runtime and
+// process collectors.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+var (
+	// HTTPRequestDuration is labelled with the route template, e.g.
+	// /pages/:id, rather than the path, to keep the number of series bounded.
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests by method, route and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	RedisCommandDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Duration of Redis commands by command and result; pipelines count as one.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command", "result"})
+
+	StorageUploadDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "upload_duration_seconds",
+		Help:      "Duration of uploads to object storage by result.",
+		Buckets:   []float64{.01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"result"})
+
+	StorageUploadSize = factory.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "upload_size_bytes",
+		Help:      "Size of files uploaded to object storage.",
+		Buckets:   prometheus.ExponentialBuckets(16<<10, 4, 8), // 16 KiB to 256 MiB
+	})
+
+	OTPsSent = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "otp_sent_total",
+		Help:      "One-time passwords generated and sent.",
+	})
+
+	// OTPVerifications counts verification attempts by result, "verified"
+	// or "failed".
+	OTPVerifications = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "otp_verifications_total",
+		Help:      "One-time password verification attempts by result.",
+	}, []string{"result"})
+
+	// Logins counts password logins by result, "success" or "failure".
+	Logins = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Password login attempts by result.",
+	}, []string{"result"})
+
+	PagesCreated = factory.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pages_created_total",
+		Help:      "Pages submitted.",
+	})
+
+	RateLimited = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests rejected by the rate limiter, by route group.",
+	}, []string{"group"})
+)
+
+// RegisterDB exports the connection pool statistics of db, labelled with
+// name.
+func RegisterDB(db *sql.DB, name string) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// Result labels the outcome of an operation as "ok" or "error".
+func Result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/logging"
+	"github.com/cavidyrm/instawall/internal/validator"
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEProblemJSON is the media type of RFC 7807 problem details.
+const MIMEProblemJSON = "application/problem+json"
+
+// HTTPErrorHandler writes every error returned by a handler or middleware as
+// RFC 7807 problem details with a stable error code and the request ID.
+// Validation failures list each invalid field, translated into the
+// negotiated locale.
+// Typed application errors keep their message; other errors are logged and
+// reported as a generic internal error so their details don't leak.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, code, detail := http.StatusInternalServerError, "internal_error", "internal server error"
+	var extra map[string]any
+
+	appErr, ok := apperror.As(err)
+	if !ok {
+		// Database errors a usecase did not translate itself.
+		appErr, _ = apperror.As(apperror.FromDB(err, "resource"))
+	}
+	var httpErr *echo.HTTPError
+	switch {
+	case appErr != nil:
+		status, code, detail, extra = statusOf(appErr.Kind), appErr.Code, appErr.Message, appErr.Extra
+		if appErr.RetryAfter > 0 {
+			c.Response().Header().Set("Retry-After", strconv.Itoa(seconds(appErr.RetryAfter)))
+		}
+	case errors.As(err, &httpErr):
+		// Routing, binding and body-limit errors raised by Echo itself.
+		status, code = httpErr.Code, codeForStatus(httpErr.Code)
+		detail = strings.ToLower(http.StatusText(httpErr.Code))
+		if msg, ok := httpErr.Message.(string); ok && status < http.StatusInternalServerError {
+			detail = msg
+		}
+	}
+
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+	if requestID == "" {
+		requestID = c.Request().Header.Get(echo.HeaderXRequestID)
+	}
+	if status >= http.StatusInternalServerError {
+		logging.FromContext(ctx).ErrorContext(ctx, "request failed", "method", c.Request().Method, "path", c.Request().URL.Path, "err", err)
+	}
+
+	body := make(echo.Map, len(extra)+8)
+	for k, v := range extra {
+		body[k] = v
+	}
+	var fieldErrs validator.FieldErrors
+	if errors.As(err, &fieldErrs) {
+		body["errors"] = fieldErrs.Translate(Locale(c))
+	}
+	body["type"] = "about:blank"
+	body["title"] = http.StatusText(status)
+	body["status"] = status
+	body["detail"] = detail
+	body["instance"] = c.Request().URL.Path
+	body["code"] = code
+	if requestID != "" {
+		body["request_id"] = requestID
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = writeProblem(c, status, body)
+	}
+	if err != nil {
+		logging.FromContext(ctx).ErrorContext(ctx, "write error response", "err", err)
+	}
+}
+
+func writeProblem(c echo.Context, status int, body echo.Map) error {
+	c.Response().Header().Set(echo.HeaderContentType, MIMEProblemJSON)
+	c.Response().WriteHeader(status)
+	return c.Echo().JSONSerializer.Serialize(c, body, "")
+}
+
+func statusOf(kind apperror.Kind) int {
+	switch kind {
+	case apperror.KindValidation:
+		return http.StatusBadRequest
+	case apperror.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperror.KindForbidden:
+		return http.StatusForbidden
+	case apperror.KindNotFound:
+		return http.StatusNotFound
+	case apperror.KindConflict:
+		return http.StatusConflict
+	case apperror.KindGone:
+		return http.StatusGone
+	case apperror.KindUnprocessable:
+		return http.StatusUnprocessableEntity
+	case apperror.KindRateLimited:
+		return http.StatusTooManyRequests
+	case apperror.KindPreconditionFailed:
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusInternalServerError
+}
+
+// codeForStatus derives an error code from a status text, e.g.
+// "method_not_allowed" for 405.
+func codeForStatus(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return fmt.Sprintf("http_%d", status)
+	}
+	return strings.ToLower(strings.ReplaceAll(text, " ", "_"))
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ETag returns the entity tag for a row version served in a locale, e.g.
+// W/"7-fa". The locale is part of the tag because translated fields make
+// the body differ between locales at the same version. It is weak because
+// counters such as favorite_count change without a new version.
+func ETag(version int64, locale string) string {
+	tag := strconv.FormatInt(version, 10)
+	if locale != "" {
+		tag += "-" + locale
+	}
+	return `W/"` + tag + `"`
+}
+
+// SetETag sets the ETag header for a row version in the negotiated locale.
+func SetETag(c echo.Context, version int64) {
+	c.Response().Header().Set("ETag", ETag(version, Locale(c)))
+}
+
+// NotModified sets the ETag header for a row version and reports whether
+// the request's If-None-Match already names it in the negotiated locale, in
+// which case the caller should respond with 304.
+func NotModified(c echo.Context, version int64) bool {
+	SetETag(c, version)
+	header := c.Request().Header.Get("If-None-Match")
+	if header == "" {
+		return false
+	}
+	locale := Locale(c)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return true
+		}
+		if v, l, ok := parseETag(tag); ok && v == version && l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatch returns the row version a write is conditional on, or nil if the
+// request has no If-Match header or it is "*". A tag that is not one of ours
+// yields version 0, which never matches, so the write fails with 412.
+//
+// Versions are compared ignoring the weak prefix and the locale: they
+// identify the state of the row, not a byte-exact representation.
+func IfMatch(c echo.Context) *int64 {
+	header := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+	v, _, ok := parseETag(header)
+	if !ok {
+		v = 0
+	}
+	return &v
+}
+
+func parseETag(tag string) (version int64, locale string, ok bool) {
+	tag = strings.TrimPrefix(tag, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, "", false
+	}
+	num, locale, _ := strings.Cut(tag[1:len(tag)-1], "-")
+	v, err := strconv.ParseInt(num, 10, 64)
+	return v, locale, err == nil
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+)
+
+func newETagContext(locale, header, value string) echo.Context {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+	c := echo.New().NewContext(req, httptest.NewRecorder())
+	c.Set("locale", locale)
+	return c
+}
+
+func TestNotModified(t *testing.T) {
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		want        bool
+	}{
+		{"no header", "", false},
+		{"same version and locale", `W/"7-fa"`, true},
+		{"one of several", `W/"6-fa", W/"7-fa"`, true},
+		{"any", "*", true},
+		{"other locale", `W/"7-en"`, false},
+		{"other version", `W/"6-fa"`, false},
+		{"not one of ours", `"abc"`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newETagContext("fa", "If-None-Match", tt.ifNoneMatch)
+			if got := NotModified(c, 7); got != tt.want {
+				t.Errorf("NotModified = %v, want %v", got, tt.want)
+			}
+			if got := c.Response().Header().Get("ETag"); got != `W/"7-fa"` {
+				t.Errorf("ETag = %s, want W/\"7-fa\"", got)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		ifMatch string
+		want    *int64
+	}{
+		{"", nil},
+		{"*", nil},
+		{`W/"7-en"`, ptr(7)},
+		{`"7"`, ptr(7)},
+		{"garbage", ptr(0)},
+	}
+	for _, tt := range tests {
+		got := IfMatch(newETagContext("fa", "If-Match", tt.ifMatch))
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("IfMatch(%q) = %v, want %v", tt.ifMatch, got, tt.want)
+		}
+	}
+}
+
+func ptr(v int64) *int64 { return &v }
@@ -1,15 +1,19 @@
 package middleware
 
 import (
-	"net/http"
+	"errors"
+	"strings"
 	"time"
 
+	"github.com/cavidyrm/instawall/internal/apperror"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
-// JWTSecret should be loaded from a secure configuration in production.
-var JWTSecret = []byte("your-very-secret-key")
+// JWTSecret signs and verifies access tokens. It is set from auth.jwt_secret
+// at startup.
+var JWTSecret []byte
 
 // JWTCustomClaims are the claims for a standard logged-in user.
 type JWTCustomClaims struct {
@@ -57,32 +61,77 @@ func JWTAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		authHeader := c.Request().Header.Get("Authorization")
 		if authHeader == "" {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "missing or malformed jwt"})
+			return apperror.Unauthorized("missing or malformed jwt")
 		}
-		tokenString := authHeader[len("Bearer "):]
-		token, err := jwt.ParseWithClaims(tokenString, &JWTCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return JWTSecret, nil
-		})
-		if err != nil || !token.Valid {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid or expired jwt"})
+		claims, err := parseUserToken(authHeader)
+		if err != nil {
+			return apperror.Unauthorized(err.Error())
 		}
-		claims, ok := token.Claims.(*JWTCustomClaims)
-		if !ok {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid jwt claims"})
+		setUserClaims(c, claims)
+		return next(c)
+	}
+}
+
+// OptionalJWTAuthMiddleware populates the user from a valid token if one is
+// sent, and otherwise lets the request through anonymously.
+func OptionalJWTAuthMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if authHeader := c.Request().Header.Get("Authorization"); authHeader != "" {
+			if claims, err := parseUserToken(authHeader); err == nil {
+				setUserClaims(c, claims)
+			}
 		}
-		c.Set("user_id", claims.UserID)
-		c.Set("user_name", claims.Name)
-		c.Set("user_role", claims.Role)
 		return next(c)
 	}
 }
 
+// parseUserToken validates a "Bearer <token>" header and returns its claims.
+func parseUserToken(authHeader string) (*JWTCustomClaims, error) {
+	tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok {
+		return nil, errors.New("missing or malformed jwt")
+	}
+	token, err := jwt.ParseWithClaims(tokenString, &JWTCustomClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return JWTSecret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid or expired jwt")
+	}
+	claims, ok := token.Claims.(*JWTCustomClaims)
+	if !ok {
+		return nil, errors.New("invalid jwt claims")
+	}
+	return claims, nil
+}
+
+// ViewerID returns the authenticated user's ID, or uuid.Nil for anonymous
+// requests that passed through OptionalJWTAuthMiddleware.
+func ViewerID(c echo.Context) uuid.UUID {
+	if s, ok := c.Get("user_id").(string); ok {
+		if id, err := uuid.Parse(s); err == nil {
+			return id
+		}
+	}
+	return uuid.Nil
+}
+
+func setUserClaims(c echo.Context, claims *JWTCustomClaims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("user_name", claims.Name)
+	c.Set("user_role", claims.Role)
+}
+
+// IsAdmin reports whether the authenticated user is an admin.
+func IsAdmin(c echo.Context) bool {
+	role, ok := c.Get("user_role").(string)
+	return ok && role == "admin"
+}
+
 // AdminOnlyMiddleware must be used *after* JWTAuthMiddleware.
 func AdminOnlyMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
-		role, ok := c.Get("user_role").(string)
-		if !ok || role != "admin" {
-			return c.JSON(http.StatusForbidden, echo.Map{"error": "Forbidden: Admins only"})
+		if !IsAdmin(c) {
+			return apperror.Forbidden("admins only")
 		}
 		return next(c)
 	}
@@ -93,18 +142,18 @@ func RegistrationTokenMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		authHeader := c.Request().Header.Get("Authorization")
 		if authHeader == "" {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "missing registration token"})
+			return apperror.Unauthorized("missing registration token")
 		}
 		tokenString := authHeader[len("Bearer "):]
 		token, err := jwt.ParseWithClaims(tokenString, &RegistrationClaims{}, func(token *jwt.Token) (interface{}, error) {
 			return JWTSecret, nil
 		})
 		if err != nil || !token.Valid {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid or expired registration token"})
+			return apperror.Unauthorized("invalid or expired registration token")
 		}
 		claims, ok := token.Claims.(*RegistrationClaims)
 		if !ok {
-			return c.JSON(http.StatusUnauthorized, echo.Map{"error": "invalid registration token claims"})
+			return apperror.Unauthorized("invalid registration token claims")
 		}
 		c.Set("verified_mobile", claims.MobileNumber)
 		return next(c)
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/cavidyrm/instawall/pkg/i18n"
+	"github.com/labstack/echo/v4"
+)
+
+// LocaleMiddleware negotiates the response locale from the "lang" query
+// parameter or the Accept-Language header.
+func LocaleMiddleware(n *i18n.Negotiator) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			locale := n.Negotiate(c.QueryParam("lang"), c.Request().Header.Get("Accept-Language"))
+			c.Set("locale", locale)
+			c.Response().Header().Set("Content-Language", locale)
+			c.Response().Header().Add(echo.HeaderVary, "Accept-Language")
+			return next(c)
+		}
+	}
+}
+
+// Locale returns the locale negotiated by LocaleMiddleware, or "" if it did
+// not run.
+func Locale(c echo.Context) string {
+	locale, _ := c.Get("locale").(string)
+	return locale
+}
@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cavidyrm/instawall/config"
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/logging"
+	"github.com/cavidyrm/instawall/internal/metrics"
+	"github.com/cavidyrm/instawall/internal/ratelimit"
+	"github.com/labstack/echo/v4"
+)
+
+// RateLimitMiddleware limits the requests to each configured group of
+// routes, per user for requests with a valid access token and per client IP
+// otherwise. Every limited response carries RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers; rejected
+// requests get a 429 with Retry-After. If the store fails, requests are let
+// through rather than taking the API down with it.
+func RateLimitMiddleware(store ratelimit.Store, cfg config.RateLimitConfig) echo.MiddlewareFunc {
+	groups := make([]routeGroup, 0, len(cfg.Groups)+1)
+	for _, g := range cfg.Groups {
+		groups = append(groups, newRouteGroup(g.Name, g.RateLimitRule, g.Routes))
+	}
+	groups = append(groups, newRouteGroup("default", cfg.Default, []string{"/*"}))
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if isProbe(c) {
+				return next(c)
+			}
+			group := groupFor(groups, c)
+			if group.rule.Limit == 0 {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			res, err := store.Take(ctx, group.name+":"+rateLimitKey(c), group.rule)
+			if err != nil {
+				logging.FromContext(ctx).WarnContext(ctx, "rate limit store failed, allowing request", "group", group.name, "err", err)
+				return next(c)
+			}
+			h := c.Response().Header()
+			h.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			h.Set("RateLimit-Reset", strconv.Itoa(seconds(res.Reset)))
+			h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", group.rule.Limit, seconds(group.rule.Window)))
+			if !res.Allowed {
+				metrics.RateLimited.WithLabelValues(group.name).Inc()
+				return apperror.RateLimited("too many requests, try again later", res.RetryAfter)
+			}
+			return next(c)
+		}
+	}
+}
+
+// routeGroup is a rate limit group with its routes parsed.
+type routeGroup struct {
+	name   string
+	rule   ratelimit.Rule
+	routes []routePattern
+}
+
+// routePattern matches the method and registered path of a request.
+type routePattern struct {
+	method string // "" matches every method
+	path   string
+	prefix bool // the path ended in *
+}
+
+func newRouteGroup(name string, rule config.RateLimitRule, routes []string) routeGroup {
+	g := routeGroup{name: name, rule: ratelimit.Rule{Limit: rule.Limit, Window: rule.Window}}
+	for _, route := range routes {
+		fields := strings.Fields(route)
+		var p routePattern
+		if len(fields) == 2 {
+			if fields[0] != "*" {
+				p.method = fields[0]
+			}
+			fields = fields[1:]
+		}
+		if len(fields) != 1 {
+			continue // rejected by config validation
+		}
+		p.path, p.prefix = strings.CutSuffix(fields[0], "*")
+		g.routes = append(g.routes, p)
+	}
+	return g
+}
+
+// groupFor returns the first group with a route matching the request. The
+// last group, the default, matches every request.
+func groupFor(groups []routeGroup, c echo.Context) routeGroup {
+	method, path := c.Request().Method, c.Path()
+	for _, g := range groups {
+		for _, p := range g.routes {
+			if p.method != "" && p.method != method {
+				continue
+			}
+			if path == p.path || p.prefix && strings.HasPrefix(path, p.path) {
+				return g
+			}
+		}
+	}
+	return groups[len(groups)-1]
+}
+
+// rateLimitKey identifies the caller. Route-level auth middleware has not run
+// yet, so the access token is checked here; an invalid one counts against
+// the client IP.
+func rateLimitKey(c echo.Context) string {
+	if authHeader := c.Request().Header.Get("Authorization"); authHeader != "" {
+		if claims, err := parseUserToken(authHeader); err == nil && claims.UserID != "" {
+			return "user:" + claims.UserID
+		}
+	}
+	return "ip:" + c.RealIP()
+}
+
+// seconds rounds d up to whole seconds, as the rate limit headers expect.
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+// IPExtractor returns the client IP extractor for Echo. The X-Forwarded-For
+// header is only believed when the request comes through one of the trusted
+// proxy ranges; without any, the address of the connection is used.
+func IPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	if len(trustedProxies) == 0 {
+		return echo.ExtractIPDirect(), nil
+	}
+	opts := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, cidr := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", cidr, err)
+		}
+		opts = append(opts, echo.TrustIPRange(ipNet))
+	}
+	return echo.ExtractIPFromXFFHeader(opts...), nil
+}
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cavidyrm/instawall/config"
+	"github.com/cavidyrm/instawall/internal/ratelimit"
+	"github.com/labstack/echo/v4"
+)
+
+// newRateLimitedEcho serves a few routes behind RateLimitMiddleware with
+// the default groups, each limited to limit requests per hour.
+func newRateLimitedEcho(t *testing.T, store ratelimit.Store, limit int) *echo.Echo {
+	t.Helper()
+	secret := JWTSecret
+	JWTSecret = []byte("test-secret")
+	t.Cleanup(func() { JWTSecret = secret })
+
+	cfg := config.Default().RateLimit
+	for i := range cfg.Groups {
+		cfg.Groups[i].RateLimitRule = config.RateLimitRule{Limit: limit, Window: time.Hour}
+	}
+	cfg.Default = config.RateLimitRule{Limit: limit, Window: time.Hour}
+
+	e := echo.New()
+	e.HTTPErrorHandler = HTTPErrorHandler
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(RateLimitMiddleware(store, cfg))
+	ok := func(c echo.Context) error { return c.NoContent(http.StatusNoContent) }
+	e.POST("/auth/login", ok)
+	e.GET("/pages", ok)
+	e.POST("/pages", ok)
+	e.POST("/pages/:id/favorite", ok)
+	e.GET("/healthz", ok)
+	return e
+}
+
+type request struct {
+	method, path, ip, user, contentType string
+}
+
+func serve(e *echo.Echo, r request) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(r.method, r.path, strings.NewReader("{}"))
+	req.RemoteAddr = r.ip + ":1234"
+	if r.contentType != "" {
+		req.Header.Set(echo.HeaderContentType, r.contentType)
+	}
+	if r.user != "" {
+		token, _ := GenerateToken(r.user, "", "user")
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitRejectsWithRetryAfter(t *testing.T) {
+	e := newRateLimitedEcho(t, ratelimit.NewMemoryStore(), 2)
+	r := request{method: http.MethodGet, path: "/pages", ip: "192.0.2.1"}
+
+	for i := range 2 {
+		rec := serve(e, r)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d, want 204", i+1, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "2" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want 2", i+1, got)
+		}
+		if got, want := rec.Header().Get("RateLimit-Remaining"), []string{"1", "0"}[i]; got != want {
+			t.Errorf("request %d: RateLimit-Remaining = %q, want %q", i+1, got, want)
+		}
+		if got := rec.Header().Get("RateLimit-Policy"); got != "2;w=3600" {
+			t.Errorf("request %d: RateLimit-Policy = %q, want 2;w=3600", i+1, got)
+		}
+	}
+
+	rec := serve(e, r)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", rec.Code)
+	}
+	// A token comes back every 30 minutes.
+	if got := rec.Header().Get("Retry-After"); got != "1800" {
+		t.Errorf("Retry-After = %q, want 1800", got)
+	}
+	if got := rec.Header().Get("RateLimit-Remaining"); got != "0" {
+		t.Errorf("RateLimit-Remaining = %q, want 0", got)
+	}
+	if !strings.Contains(rec.Body.String(), `"rate_limited"`) {
+		t.Errorf("body = %s, want the rate_limited code", rec.Body)
+	}
+}
+
+func TestRateLimitKeys(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, then request
+		limited     bool
+	}{
+		{
+			name:    "same anonymous IP",
+			first:   request{ip: "192.0.2.1"},
+			then:    request{ip: "192.0.2.1"},
+			limited: true,
+		},
+		{
+			name:  "different anonymous IPs",
+			first: request{ip: "192.0.2.1"},
+			then:  request{ip: "192.0.2.2"},
+		},
+		{
+			name:    "same user from different IPs",
+			first:   request{ip: "192.0.2.1", user: "u1"},
+			then:    request{ip: "192.0.2.2", user: "u1"},
+			limited: true,
+		},
+		{
+			name:  "different users from the same IP",
+			first: request{ip: "192.0.2.1", user: "u1"},
+			then:  request{ip: "192.0.2.1", user: "u2"},
+		},
+		{
+			name:  "user and anonymous from the same IP",
+			first: request{ip: "192.0.2.1", user: "u1"},
+			then:  request{ip: "192.0.2.1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newRateLimitedEcho(t, ratelimit.NewMemoryStore(), 1)
+			tt.first.method, tt.first.path = http.MethodGet, "/pages"
+			tt.then.method, tt.then.path = http.MethodGet, "/pages"
+
+			if rec := serve(e, tt.first); rec.Code != http.StatusNoContent {
+				t.Fatalf("first request: status = %d, want 204", rec.Code)
+			}
+			want := http.StatusNoContent
+			if tt.limited {
+				want = http.StatusTooManyRequests
+			}
+			if rec := serve(e, tt.then); rec.Code != want {
+				t.Errorf("second request: status = %d, want %d", rec.Code, want)
+			}
+		})
+	}
+}
+
+func TestRateLimitGroups(t *testing.T) {
+	tests := []struct {
+		name        string
+		first, then request
+		limited     bool
+	}{
+		{
+			name:    "auth routes share a group",
+			first:   request{method: http.MethodPost, path: "/auth/login"},
+			then:    request{method: http.MethodPost, path: "/auth/login"},
+			limited: true,
+		},
+		{
+			name:  "auth does not count against reads",
+			first: request{method: http.MethodPost, path: "/auth/login"},
+			then:  request{method: http.MethodGet, path: "/pages"},
+		},
+		{
+			name:    "a JSON body counts against uploads like a multipart one",
+			first:   request{method: http.MethodPost, path: "/pages", contentType: echo.MIMEMultipartForm + "; boundary=x"},
+			then:    request{method: http.MethodPost, path: "/pages", contentType: echo.MIMEApplicationJSON},
+			limited: true,
+		},
+		{
+			name:  "uploads do not count against other writes",
+			first: request{method: http.MethodPost, path: "/pages"},
+			then:  request{method: http.MethodPost, path: "/pages/p1/favorite"},
+		},
+		{
+			name:  "writes do not count against reads",
+			first: request{method: http.MethodPost, path: "/pages/p1/favorite"},
+			then:  request{method: http.MethodGet, path: "/pages"},
+		},
+		{
+			name:  "probes are not limited",
+			first: request{method: http.MethodGet, path: "/healthz"},
+			then:  request{method: http.MethodGet, path: "/healthz"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newRateLimitedEcho(t, ratelimit.NewMemoryStore(), 1)
+			tt.first.ip, tt.then.ip = "192.0.2.1", "192.0.2.1"
+
+			if rec := serve(e, tt.first); rec.Code != http.StatusNoContent {
+				t.Fatalf("first request: status = %d, want 204", rec.Code)
+			}
+			want := http.StatusNoContent
+			if tt.limited {
+				want = http.StatusTooManyRequests
+			}
+			if rec := serve(e, tt.then); rec.Code != want {
+				t.Errorf("second request: status = %d, want %d", rec.Code, want)
+			}
+		})
+	}
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Rule) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("store down")
+}
+
+func TestRateLimitAllowsRequestsWhenStoreFails(t *testing.T) {
+	e := newRateLimitedEcho(t, failingStore{}, 1)
+	for i := range 3 {
+		rec := serve(e, request{method: http.MethodGet, path: "/pages", ip: "192.0.2.1"})
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("request %d: status = %d, want 204", i+1, rec.Code)
+		}
+		if got := rec.Header().Get("RateLimit-Limit"); got != "" {
+			t.Errorf("request %d: RateLimit-Limit = %q, want none", i+1, got)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+)
+
+// TracingMiddleware starts a server span for each request, continuing the
+// trace of the caller's traceparent header if it sent one. Probes of the
+// health endpoints are not traced.
+func TracingMiddleware(service string) echo.MiddlewareFunc {
+	return otelecho.Middleware(service, otelecho.WithSkipper(isProbe))
+}
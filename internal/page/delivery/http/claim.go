@@ -0,0 +1,126 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
+	"github.com/cavidyrm/instawall/internal/page/usecase"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ClaimHandler struct {
+	claimUsecase *usecase.ClaimUsecase
+}
+
+func RegisterClaimHandlers(e *echo.Echo, uc *usecase.ClaimUsecase) {
+	h := &ClaimHandler{claimUsecase: uc}
+
+	// Authenticated routes for users claiming a page
+	pageGroup := e.Group("/pages")
+	pageGroup.POST("/:id/claims", h.StartClaim, appMiddleware.JWTAuthMiddleware)
+	pageGroup.POST("/:id/claims/:claim_id/verify", h.VerifyClaim, appMiddleware.JWTAuthMiddleware)
+
+	// Admin-only override of page ownership
+	adminGroup := e.Group("/admin/pages")
+	adminGroup.Use(appMiddleware.JWTAuthMiddleware, appMiddleware.AdminOnlyMiddleware)
+	adminGroup.PUT("/:id/owner", h.AssignOwner)
+}
+
+// Request/Response Structs
+type ClaimResponse struct {
+	ID           uuid.UUID `json:"id"`
+	PageID       uuid.UUID `json:"page_id"`
+	Code         string    `json:"code"`
+	Status       string    `json:"status"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	Instructions string    `json:"instructions,omitempty"`
+}
+type AssignOwnerRequest struct {
+	UserID   string `json:"user_id" validate:"required,uuid"`
+	Verified *bool  `json:"verified"`
+}
+
+// --- Handler Methods ---
+
+func (h *ClaimHandler) StartClaim(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	claim, err := h.claimUsecase.StartClaim(c.Request().Context(), pageID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, ClaimResponse{
+		ID:           claim.ID,
+		PageID:       claim.PageID,
+		Code:         claim.Code,
+		Status:       claim.Status,
+		ExpiresAt:    claim.ExpiresAt,
+		Instructions: "Add the code to your Instagram bio, then call the verify endpoint. You can remove it once the claim is verified.",
+	})
+}
+
+func (h *ClaimHandler) VerifyClaim(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+	claimID, err := uuid.Parse(c.Param("claim_id"))
+	if err != nil {
+		return apperror.Validation("invalid claim ID")
+	}
+
+	claim, err := h.claimUsecase.VerifyClaim(c.Request().Context(), pageID, claimID, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, ClaimResponse{
+		ID:        claim.ID,
+		PageID:    claim.PageID,
+		Code:      claim.Code,
+		Status:    claim.Status,
+		ExpiresAt: claim.ExpiresAt,
+	})
+}
+
+func (h *ClaimHandler) AssignOwner(c echo.Context) error {
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+	var req AssignOwnerRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	userID, err := uuid.Parse(req.UserID)
+	if err != nil {
+		return apperror.Validation("invalid user ID")
+	}
+	verified := true
+	if req.Verified != nil {
+		verified = *req.Verified
+	}
+
+	if err := h.claimUsecase.AssignOwner(c.Request().Context(), pageID, userID, verified); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,148 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
+	"github.com/cavidyrm/instawall/internal/page/usecase"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ReorderImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"image_ids" validate:"required,min=1,unique"`
+}
+
+func (h *PageHandler) GetImages(c echo.Context) error {
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	images, err := h.pageUsecase.GetImages(c.Request().Context(), pageID, appMiddleware.ViewerID(c))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, images)
+}
+
+func (h *PageHandler) AddImages(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	images, err := formImages(c, "images")
+	if err != nil {
+		return err
+	}
+	defer closeImages(images)
+
+	added, err := h.pageUsecase.AddImages(c.Request().Context(), pageID, userID, images)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, added)
+}
+
+func (h *PageHandler) DeleteImage(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+	imageID, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		return apperror.Validation("invalid image ID")
+	}
+
+	if err := h.pageUsecase.DeleteImage(c.Request().Context(), pageID, imageID, userID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PageHandler) ReorderImages(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	var req ReorderImagesRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	if err := h.pageUsecase.ReorderImages(c.Request().Context(), pageID, userID, req.ImageIDs); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PageHandler) SetCoverImage(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+	imageID, err := uuid.Parse(c.Param("image_id"))
+	if err != nil {
+		return apperror.Validation("invalid image ID")
+	}
+
+	if err := h.pageUsecase.SetCoverImage(c.Request().Context(), pageID, imageID, userID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// formImages opens the files uploaded under a multipart form field. It
+// returns no images for requests that are not multipart. Callers must
+// closeImages the result.
+func formImages(c echo.Context, field string) ([]usecase.ImageUpload, error) {
+	form, err := c.MultipartForm()
+	if errors.Is(err, http.ErrNotMultipart) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var uploads []usecase.ImageUpload
+	for _, fh := range form.File[field] {
+		src, err := fh.Open()
+		if err != nil {
+			closeImages(uploads)
+			return nil, err
+		}
+		uploads = append(uploads, usecase.ImageUpload{File: src, Size: fh.Size, Name: fh.Filename})
+	}
+	return uploads, nil
+}
+
+func closeImages(uploads []usecase.ImageUpload) {
+	for _, u := range uploads {
+		if closer, ok := u.File.(io.Closer); ok {
+			closer.Close()
+		}
+	}
+}
@@ -1,33 +1,102 @@
 package http
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/cavidyrm/instawall/internal/apperror"
 	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
+	"github.com/cavidyrm/instawall/internal/page/domain"
 	"github.com/cavidyrm/instawall/internal/page/usecase"
+	tagDomain "github.com/cavidyrm/instawall/internal/tag/domain"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
+// ViewRecorder counts page views for analytics.
+type ViewRecorder interface {
+	RecordView(ctx context.Context, pageID uuid.UUID, userAgent string) error
+}
+
 type PageHandler struct {
 	pageUsecase *usecase.PageUsecase
+	views       ViewRecorder
+	logger      *slog.Logger
 }
 
-func RegisterPageHandlers(e *echo.Echo, uc *usecase.PageUsecase) {
-	h := &PageHandler{pageUsecase: uc}
+func RegisterPageHandlers(e *echo.Echo, uc *usecase.PageUsecase, views ViewRecorder, logger *slog.Logger) {
+	h := &PageHandler{pageUsecase: uc, views: views, logger: logger}
 	pageGroup := e.Group("/pages")
 
-	// Public routes to view pages
-	pageGroup.GET("", h.GetAllPages)
-	pageGroup.GET("/:id", h.GetPage)
+	// Public routes to view pages; a token is optional and personalizes is_favorited
+	pageGroup.GET("", h.GetAllPages, appMiddleware.OptionalJWTAuthMiddleware)
+	pageGroup.GET("/:id", h.GetPage, appMiddleware.OptionalJWTAuthMiddleware)
 
 	// Authenticated routes to manage pages
 	pageGroup.POST("", h.CreatePage, appMiddleware.JWTAuthMiddleware)
+	pageGroup.PATCH("/:id", h.UpdatePage, appMiddleware.JWTAuthMiddleware)
 	pageGroup.PUT("/:id", h.UpdatePage, appMiddleware.JWTAuthMiddleware)
 	pageGroup.DELETE("/:id", h.DeletePage, appMiddleware.JWTAuthMiddleware)
+
+	// Favorites
+	pageGroup.POST("/:id/favorite", h.AddFavorite, appMiddleware.JWTAuthMiddleware)
+	pageGroup.DELETE("/:id/favorite", h.RemoveFavorite, appMiddleware.JWTAuthMiddleware)
+	e.GET("/users/me/favorites", h.GetMyFavorites, appMiddleware.JWTAuthMiddleware)
+
+	// Pages by owner
+	e.GET("/users/me/pages", h.GetMyPages, appMiddleware.JWTAuthMiddleware)
+	e.GET("/users/me/drafts", h.GetMyDrafts, appMiddleware.JWTAuthMiddleware)
+	e.GET("/users/:id/pages", h.GetUserPages, appMiddleware.OptionalJWTAuthMiddleware)
+
+	// Admin-only management of translated descriptions
+	adminGroup := e.Group("/admin/pages")
+	adminGroup.Use(appMiddleware.JWTAuthMiddleware, appMiddleware.AdminOnlyMiddleware)
+	adminGroup.GET("/:id/translations", h.GetTranslations)
+	adminGroup.PUT("/:id/translations/:locale", h.SetTranslation)
+	adminGroup.DELETE("/:id/translations/:locale", h.DeleteTranslation)
+
+	// Image galleries
+	pageGroup.GET("/:id/images", h.GetImages, appMiddleware.OptionalJWTAuthMiddleware)
+	pageGroup.POST("/:id/images", h.AddImages, appMiddleware.JWTAuthMiddleware)
+	pageGroup.PUT("/:id/images/order", h.ReorderImages, appMiddleware.JWTAuthMiddleware)
+	pageGroup.PUT("/:id/images/:image_id/cover", h.SetCoverImage, appMiddleware.JWTAuthMiddleware)
+	pageGroup.DELETE("/:id/images/:image_id", h.DeleteImage, appMiddleware.JWTAuthMiddleware)
+}
+
+// --- Request DTOs ---
+
+// CreatePageRequest holds the fields of a new page, sent as a multipart form
+// with its images. category_ids may be repeated or comma-separated.
+type CreatePageRequest struct {
+	Title       string   `form:"title" validate:"required,max=255"`
+	Description string   `form:"description" validate:"max=5000"`
+	Link        string   `form:"link" validate:"required,instagram"`
+	HasIssue    bool     `form:"has_issue"`
+	CategoryIDs []string `form:"category_ids" validate:"uuid_list"`
+	Status      string   `form:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt   string   `form:"publish_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// UpdatePageRequest holds the fields of a page update, sent as JSON or, to
+// add images, as a multipart form. Omitted fields are left unchanged; an
+// empty category_ids removes every category.
+type UpdatePageRequest struct {
+	Title       *string  `json:"title" form:"title" validate:"omitnil,notempty,max=255"`
+	Description *string  `json:"description" form:"description" validate:"omitnil,max=5000"`
+	Link        *string  `json:"link" form:"link" validate:"omitnil,notempty,instagram"`
+	HasIssue    *bool    `json:"has_issue" form:"has_issue"`
+	CategoryIDs []string `json:"category_ids" form:"category_ids" validate:"uuid_list"`
+	Status      string   `json:"status" form:"status" validate:"omitempty,oneof=draft scheduled published"`
+	PublishAt   string   `json:"publish_at" form:"publish_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+type TranslationRequest struct {
+	Description string `json:"description" form:"description" validate:"required,max=5000"`
 }
 
 // --- Handler Methods ---
@@ -36,74 +105,106 @@ func (h *PageHandler) CreatePage(c echo.Context) error {
 	userIDStr := c.Get("user_id").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, "Invalid user ID in token")
+		return apperror.Unauthorized("invalid user ID in token")
 	}
 
-	title := c.FormValue("title")
-	description := c.FormValue("description")
-	link := c.FormValue("link")
-	hasIssue := c.FormValue("has_issue") == "true"
-	categoryIDsStr := c.FormValue("category_ids")
-
-	categoryIDs, err := parseUUIDs(categoryIDsStr)
+	var req CreatePageRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	req.CategoryIDs = splitList(req.CategoryIDs)
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	categoryIDs, err := parseUUIDs(req.CategoryIDs)
+	if err != nil {
+		return apperror.Validation(fmt.Sprintf("invalid category_ids: %v", err))
+	}
+	publishAt, err := parseTime(req.PublishAt)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid category_ids format: %v", err))
+		return apperror.Validation("invalid publish_at, expected RFC 3339")
 	}
 
-	fileHeader, err := c.FormFile("image")
+	// "image" is the cover; further gallery images are sent as "images".
+	cover, err := formImages(c, "image")
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Image file is required")
+		return err
 	}
-	src, err := fileHeader.Open()
+	defer closeImages(cover)
+	images, err := formImages(c, "images")
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "Failed to open image file")
+		return err
+	}
+	defer closeImages(images)
+	if len(cover)+len(images) == 0 {
+		return apperror.Validation("image file is required")
 	}
-	defer src.Close()
 
 	input := usecase.CreatePageInput{
 		UserID:      userID,
-		Title:       title,
-		Description: description,
-		Link:        link,
-		HasIssue:    hasIssue,
+		Title:       req.Title,
+		Description: req.Description,
+		Link:        req.Link,
+		HasIssue:    req.HasIssue,
 		CategoryIDs: categoryIDs,
-		ImageFile:   src,
-		ImageSize:   fileHeader.Size,
-		ImageName:   fileHeader.Filename,
+		Images:      append(cover, images...),
+		Status:      domain.PageStatus(req.Status),
+		PublishAt:   publishAt,
 	}
 
 	newPage, err := h.pageUsecase.CreatePage(c.Request().Context(), input)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Failed to create page: %v", err))
+		return err
 	}
 
+	appMiddleware.SetETag(c, newPage.Version)
 	return c.JSON(http.StatusCreated, newPage)
 }
 
 func (h *PageHandler) GetPage(c echo.Context) error {
 	pageID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid page ID")
+		return apperror.Validation("invalid page ID")
 	}
 
-	p, err := h.pageUsecase.GetPage(c.Request().Context(), pageID)
+	p, err := h.pageUsecase.GetPage(c.Request().Context(), pageID, appMiddleware.ViewerID(c), appMiddleware.Locale(c))
 	if err != nil {
-		return c.JSON(http.StatusNotFound, "Page not found")
+		return err
 	}
 
+	// Revalidations answered from the client's cache are not new views.
+	if appMiddleware.NotModified(c, p.Version) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	if h.views != nil {
+		if err := h.views.RecordView(c.Request().Context(), pageID, c.Request().UserAgent()); err != nil {
+			h.logger.WarnContext(c.Request().Context(), "record page view", "page_id", pageID, "err", err)
+		}
+	}
 	return c.JSON(http.StatusOK, p)
 }
 
 func (h *PageHandler) GetAllPages(c echo.Context) error {
-	limit, _ := strconv.Atoi(c.QueryParam("limit"))
-	offset, _ := strconv.Atoi(c.QueryParam("offset"))
-	if limit == 0 {
-		limit = 10 // Default limit
+	limit, offset := pagination(c)
+	filter := domain.PageFilter{ViewerID: appMiddleware.ViewerID(c), Limit: limit, Offset: offset}
+	if s := c.QueryParam("category_id"); s != "" {
+		categoryID, err := uuid.Parse(s)
+		if err != nil {
+			return apperror.Validation("invalid category ID")
+		}
+		filter.CategoryID = &categoryID
+	}
+	if s := c.QueryParam("tag"); s != "" {
+		tag, err := tagDomain.Normalize(s)
+		if err != nil {
+			return apperror.Invalid("invalid_tag", err.Error())
+		}
+		filter.Tag = tag
 	}
 
-	pages, err := h.pageUsecase.GetAllPages(c.Request().Context(), limit, offset)
+	pages, err := h.pageUsecase.GetAllPages(c.Request().Context(), filter, appMiddleware.Locale(c))
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "Failed to retrieve pages")
+		return err
 	}
 
 	return c.JSON(http.StatusOK, pages)
@@ -113,59 +214,69 @@ func (h *PageHandler) UpdatePage(c echo.Context) error {
 	userIDStr := c.Get("user_id").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, "Invalid user ID in token")
+		return apperror.Unauthorized("invalid user ID in token")
 	}
 
 	pageID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid page ID")
+		return apperror.Validation("invalid page ID")
 	}
 
-	title := c.FormValue("title")
-	description := c.FormValue("description")
-	link := c.FormValue("link")
-	hasIssue := c.FormValue("has_issue") == "true"
-	categoryIDsStr := c.FormValue("category_ids")
-	categoryIDs, err := parseUUIDs(categoryIDsStr)
+	var req UpdatePageRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	req.CategoryIDs = splitList(req.CategoryIDs)
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	publishAt, err := parseTime(req.PublishAt)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, fmt.Sprintf("Invalid category_ids format: %v", err))
+		return apperror.Validation("invalid publish_at, expected RFC 3339")
 	}
 
 	input := usecase.UpdatePageInput{
 		PageID:      pageID,
 		UserID:      userID,
-		Title:       title,
-		Description: description,
-		Link:        link,
-		HasIssue:    hasIssue,
-		CategoryIDs: categoryIDs,
+		Title:       req.Title,
+		Description: req.Description,
+		Link:        req.Link,
+		HasIssue:    req.HasIssue,
+		Status:      domain.PageStatus(req.Status),
+		PublishAt:   publishAt,
+		Version:     appMiddleware.IfMatch(c),
 	}
-
-	// Handle optional image update
-	fileHeader, err := c.FormFile("image")
-	if err == nil { // An image was provided
-		src, err := fileHeader.Open()
+	if req.CategoryIDs != nil {
+		categoryIDs, err := parseUUIDs(req.CategoryIDs)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, "Failed to open image file")
+			return apperror.Validation(fmt.Sprintf("invalid category_ids: %v", err))
 		}
-		defer src.Close()
-		input.ImageFile = src
-		input.ImageSize = fileHeader.Size
-		input.ImageName = fileHeader.Filename
+		input.CategoryIDs = &categoryIDs
+	}
+
+	// Handle optional image updates: "image" replaces the cover and
+	// "images" are added to the gallery.
+	cover, err := formImages(c, "image")
+	if err != nil {
+		return err
+	}
+	defer closeImages(cover)
+	if len(cover) > 0 {
+		input.Cover = &cover[0]
+	}
+	images, err := formImages(c, "images")
+	if err != nil {
+		return err
 	}
+	defer closeImages(images)
+	input.Images = images
 
 	updatedPage, err := h.pageUsecase.UpdatePage(c.Request().Context(), input)
 	if err != nil {
-		// Differentiate between not found/forbidden and other errors
-		if strings.Contains(err.Error(), "forbidden") {
-			return c.JSON(http.StatusForbidden, err.Error())
-		}
-		if strings.Contains(err.Error(), "not found") {
-			return c.JSON(http.StatusNotFound, err.Error())
-		}
-		return c.JSON(http.StatusInternalServerError, fmt.Sprintf("Failed to update page: %v", err))
+		return err
 	}
 
+	appMiddleware.SetETag(c, updatedPage.Version)
 	return c.JSON(http.StatusOK, updatedPage)
 }
 
@@ -173,29 +284,217 @@ func (h *PageHandler) DeletePage(c echo.Context) error {
 	userIDStr := c.Get("user_id").(string)
 	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, "Invalid user ID in token")
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	if err := h.pageUsecase.DeletePage(c.Request().Context(), pageID, userID, appMiddleware.IfMatch(c)); err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PageHandler) AddFavorite(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	if err := h.pageUsecase.AddFavorite(c.Request().Context(), userID, pageID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PageHandler) RemoveFavorite(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	if err := h.pageUsecase.RemoveFavorite(c.Request().Context(), userID, pageID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PageHandler) GetMyFavorites(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
 	}
+	limit, offset := pagination(c)
 
+	pages, total, err := h.pageUsecase.GetFavorites(c.Request().Context(), userID, limit, offset, appMiddleware.Locale(c))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(total))
+	return c.JSON(http.StatusOK, pages)
+}
+
+func (h *PageHandler) GetMyPages(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	limit, offset := pagination(c)
+	status := domain.PageStatus(c.QueryParam("status"))
+
+	pages, total, err := h.pageUsecase.GetMyPages(c.Request().Context(), userID, status, limit, offset, appMiddleware.Locale(c))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(total))
+	return c.JSON(http.StatusOK, pages)
+}
+
+func (h *PageHandler) GetUserPages(c echo.Context) error {
+	ownerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid user ID")
+	}
+	limit, offset := pagination(c)
+
+	pages, total, err := h.pageUsecase.GetUserPages(c.Request().Context(), ownerID, appMiddleware.ViewerID(c), limit, offset, appMiddleware.Locale(c))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(total))
+	return c.JSON(http.StatusOK, pages)
+}
+
+func (h *PageHandler) GetMyDrafts(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	limit, offset := pagination(c)
+
+	pages, total, err := h.pageUsecase.GetDrafts(c.Request().Context(), userID, limit, offset, appMiddleware.Locale(c))
+	if err != nil {
+		return err
+	}
+
+	c.Response().Header().Set("X-Total-Count", strconv.Itoa(total))
+	return c.JSON(http.StatusOK, pages)
+}
+
+func (h *PageHandler) GetTranslations(c echo.Context) error {
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	translations, err := h.pageUsecase.GetTranslations(c.Request().Context(), pageID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, translations)
+}
+
+func (h *PageHandler) SetTranslation(c echo.Context) error {
 	pageID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid page ID")
+		return apperror.Validation("invalid page ID")
 	}
 
-	if err := h.pageUsecase.DeletePage(c.Request().Context(), pageID, userID); err != nil {
-		return c.JSON(http.StatusInternalServerError, "Failed to delete page")
+	var req TranslationRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 
+	t, err := h.pageUsecase.SetTranslation(c.Request().Context(), pageID, c.Param("locale"), req.Description)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, t)
+}
+
+func (h *PageHandler) DeleteTranslation(c echo.Context) error {
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+
+	if err := h.pageUsecase.DeleteTranslation(c.Request().Context(), pageID, c.Param("locale")); err != nil {
+		return err
+	}
 	return c.NoContent(http.StatusNoContent)
 }
 
-func parseUUIDs(s string) ([]uuid.UUID, error) {
+// pagination reads limit and offset query parameters with defaults.
+func pagination(c echo.Context) (limit, offset int) {
+	limit, _ = strconv.Atoi(c.QueryParam("limit"))
+	offset, _ = strconv.Atoi(c.QueryParam("offset"))
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// parseTime parses an optional RFC 3339 timestamp.
+func parseTime(s string) (*time.Time, error) {
 	if s == "" {
 		return nil, nil
 	}
-	parts := strings.Split(s, ",")
-	uuids := make([]uuid.UUID, len(parts))
-	for i, part := range parts {
-		id, err := uuid.Parse(strings.TrimSpace(part))
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// splitList splits comma-separated elements, so that form fields may be
+// sent either repeated or as a single list. Empty elements are dropped; the
+// result is nil only if ids is nil.
+func splitList(ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		for _, part := range strings.Split(id, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func parseUUIDs(ids []string) ([]uuid.UUID, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	uuids := make([]uuid.UUID, len(ids))
+	for i, s := range ids {
+		id, err := uuid.Parse(s)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/google/uuid"
+)
+
+// Claim statuses.
+const (
+	ClaimStatusPending    = "pending"
+	ClaimStatusVerified   = "verified"
+	ClaimStatusSuperseded = "superseded"
+	ClaimStatusExpired    = "expired" // the page moved to another Instagram account
+)
+
+// PageClaim is a user's request to take ownership of a page by proving
+// control of its Instagram account.
+type PageClaim struct {
+	ID              uuid.UUID  `db:"id"`
+	PageID          uuid.UUID  `db:"page_id"`
+	UserID          uuid.UUID  `db:"user_id"`
+	Code            string     `db:"code"`
+	Status          string     `db:"status"`
+	Attempts        int        `db:"attempts"`
+	PreviousOwnerID *uuid.UUID `db:"previous_owner_id"`
+	ExpiresAt       time.Time  `db:"expires_at"`
+	VerifiedAt      *time.Time `db:"verified_at"`
+	CreatedAt       time.Time  `db:"created_at"`
+}
+
+var (
+	ErrAlreadyOwner      = apperror.Conflict("already_owner", "user already owns this verified page")
+	ErrClaimNotPending   = apperror.Conflict("claim_not_pending", "claim is no longer pending")
+	ErrClaimExpired      = apperror.Gone("claim_expired", "claim has expired")
+	ErrClaimCodeNotFound = apperror.Unprocessable("claim_code_not_found", "verification code was not found in the instagram bio")
+	ErrTooManyAttempts   = apperror.New(apperror.KindRateLimited, "too_many_attempts", "too many verification attempts for this claim")
+	ErrNoInstagramLink   = apperror.Invalid("no_instagram_link", "page has no valid instagram link to verify")
+)
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageImage is one image in a page's gallery. Exactly one image of a page
+// with images is its cover, whose URL is also kept in Page.ImageURL.
+type PageImage struct {
+	ID        uuid.UUID `db:"id" json:"id"`
+	PageID    uuid.UUID `db:"page_id" json:"page_id"`
+	URL       string    `db:"url" json:"url"`
+	Position  int       `db:"position" json:"position"`
+	IsCover   bool      `db:"is_cover" json:"is_cover"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// ImageLimits bound the images of a page.
+type ImageLimits struct {
+	MaxCount int   // images per page
+	MaxSize  int64 // bytes per image
+}
+
+var (
+	// ErrTooManyImages is returned when adding images would exceed the
+	// per-page limit.
+	ErrTooManyImages = errors.New("too many images")
+	// ErrImageSetMismatch is returned when a reorder does not list exactly
+	// the page's images.
+	ErrImageSetMismatch = errors.New("image list does not match the page's images")
+)
@@ -1,19 +1,115 @@
 package domain
 
 import (
-	"github.com/google/uuid"
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 // Page represents the core Page entity in the domain layer.
 type Page struct {
-	ID          uuid.UUID `db:"id"`
-	UserID      uuid.UUID `db:"user_id"`
-	Title       string    `db:"title"`
-	Description string    `db:"description"`
-	ImageURL    string    `db:"image_url"`
-	Link        string    `db:"link"`
-	HasIssue    bool      `db:"has_issue"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
+	ID              uuid.UUID      `db:"id" json:"id"`
+	UserID          uuid.UUID      `db:"user_id" json:"user_id"`
+	Title           string         `db:"title" json:"title"`
+	Description     string         `db:"description" json:"description"`
+	ImageURL        string         `db:"image_url" json:"image_url"`
+	Link            string         `db:"link" json:"link"`
+	InstagramHandle *string        `db:"instagram_handle" json:"instagram_handle"`
+	HasIssue        bool           `db:"has_issue" json:"has_issue"`
+	Verified        bool           `db:"verified" json:"verified"`
+	FavoriteCount   int            `db:"favorite_count" json:"favorite_count"`
+	Status          PageStatus     `db:"status" json:"status"`
+	PublishAt       *time.Time     `db:"publish_at" json:"publish_at"`     // when a scheduled page is due to be published
+	PublishedAt     *time.Time     `db:"published_at" json:"published_at"` // nil until first published
+	IsFavorited     bool           `db:"is_favorited" json:"is_favorited"` // relative to the requesting user; not a column
+	Tags            pq.StringArray `db:"tags" json:"tags"`                 // tag names, loaded with listings; not a column
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+	Version         int64          `db:"version" json:"version"` // bumped by the database whenever the page's content changes
+
+	// Link check bookkeeping; internal to the link checker.
+	LinkCheckedAt     *time.Time `db:"link_checked_at" json:"-"`
+	LinkStatusCode    *int       `db:"link_status_code" json:"-"`
+	LinkFailureStreak int        `db:"link_failure_streak" json:"-"`
+	LinkNextCheckAt   *time.Time `db:"link_next_check_at" json:"-"`
+
+	Images []PageImage `db:"-" json:"images,omitempty"` // gallery in display order, loaded for single pages only
+
+	// Set when the page is shown in a listing as a paid placement.
+	Sponsored   bool       `db:"sponsored" json:"sponsored"`
+	PromotionID *uuid.UUID `db:"promotion_id" json:"promotion_id,omitempty"`
+}
+
+// PageStatus is the publishing state of a page. Only published pages are
+// visible to anyone but their owner.
+type PageStatus string
+
+const (
+	PageStatusDraft     PageStatus = "draft"
+	PageStatusScheduled PageStatus = "scheduled"
+	PageStatusPublished PageStatus = "published"
+)
+
+// Valid reports whether s is a known status.
+func (s PageStatus) Valid() bool {
+	switch s {
+	case PageStatusDraft, PageStatusScheduled, PageStatusPublished:
+		return true
+	}
+	return false
+}
+
+// PageFilter selects and paginates pages for listings.
+type PageFilter struct {
+	ViewerID   uuid.UUID  // uuid.Nil for anonymous requests
+	CategoryID *uuid.UUID // only pages in this category
+	Tag        string     // only pages with this normalized tag
+	Limit      int
+	Offset     int
+}
+
+// PageUpdate lists what is saved along with a page's own fields, in the same
+// transaction, when it is updated.
+type PageUpdate struct {
+	Version     *int64       // the version the client read (If-Match), if it sent one
+	CategoryIDs *[]uuid.UUID // replaces the page's categories when set
+	ImageURLs   []string     // appended to the gallery
+	NewCover    bool         // the first of ImageURLs becomes the cover
+	MaxImages   int
+}
+
+// LinkCheckResult is the outcome of a single health check of a page's link.
+type LinkCheckResult struct {
+	PageID        uuid.UUID
+	StatusCode    int // 0 when the request failed before a response was received
+	FailureStreak int
+	MarkIssue     bool // set has_issue on the page
+	CheckedAt     time.Time
+	NextCheckAt   time.Time
+}
+
+// ErrHandleTaken is returned by the repository when another page already
+// uses the same Instagram handle.
+var ErrHandleTaken = errors.New("instagram handle already taken")
+
+// ErrNotOwner is returned when a user changes a page they don't own.
+var ErrNotOwner = apperror.Forbidden("user does not own this page")
+
+// ErrVersionMismatch is returned when a conditional write expects a version
+// of the page other than the current one.
+var ErrVersionMismatch = apperror.PreconditionFailed("version_mismatch", "page has been modified since it was read")
+
+// DuplicatePageError is returned when a page for the same Instagram account
+// has already been submitted.
+type DuplicatePageError struct {
+	ExistingPageID uuid.UUID
+	Handle         string
+}
+
+func (e *DuplicatePageError) Error() string {
+	return fmt.Sprintf("a page for instagram account @%s already exists", e.Handle)
 }
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PageTranslation holds a page's description in a locale other than the
+// default one.
+type PageTranslation struct {
+	PageID      uuid.UUID `db:"page_id" json:"page_id"`
+	Locale      string    `db:"locale" json:"locale"`
+	Description string    `db:"description" json:"description"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Localize replaces the descriptions of pages that have a translation,
+// keyed by page ID. Other pages keep their default-locale description.
+func Localize(pages []Page, translations map[uuid.UUID]PageTranslation) {
+	for i := range pages {
+		if t, ok := translations[pages[i].ID]; ok && t.Description != "" {
+			pages[i].Description = t.Description
+		}
+	}
+}
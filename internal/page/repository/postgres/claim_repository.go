@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/pkg/database"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ClaimRepository provides a database implementation for page ownership claims.
+type ClaimRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewClaimRepository creates a new ClaimRepository. A nil logger means
+// slog.Default().
+func NewClaimRepository(db *sqlx.DB, logger *slog.Logger) *ClaimRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ClaimRepository{db: db, logger: logger}
+}
+
+// CreateClaim saves a new pending claim, replacing any earlier pending claim
+// by the same user for the same page.
+func (r *ClaimRepository) CreateClaim(ctx context.Context, cl *domain.PageClaim) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	if _, err := tx.ExecContext(ctx, `UPDATE page_claims SET status = $1 WHERE page_id = $2 AND user_id = $3 AND status = $4`,
+		domain.ClaimStatusSuperseded, cl.PageID, cl.UserID, domain.ClaimStatusPending); err != nil {
+		return err
+	}
+
+	query := `INSERT INTO page_claims (page_id, user_id, code, expires_at)
+			  VALUES ($1, $2, $3, $4) RETURNING id, status, attempts, created_at`
+	if err := tx.QueryRowxContext(ctx, query, cl.PageID, cl.UserID, cl.Code, cl.ExpiresAt).
+		Scan(&cl.ID, &cl.Status, &cl.Attempts, &cl.CreatedAt); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetClaimByID retrieves a single claim by its ID.
+func (r *ClaimRepository) GetClaimByID(ctx context.Context, claimID uuid.UUID) (*domain.PageClaim, error) {
+	var cl domain.PageClaim
+	query := `SELECT * FROM page_claims WHERE id = $1`
+	err := r.db.GetContext(ctx, &cl, query, claimID)
+	return &cl, err
+}
+
+// IncrementClaimAttempts records a verification attempt and returns the new count.
+func (r *ClaimRepository) IncrementClaimAttempts(ctx context.Context, claimID uuid.UUID) (int, error) {
+	var attempts int
+	query := `UPDATE page_claims SET attempts = attempts + 1 WHERE id = $1 RETURNING attempts`
+	err := r.db.QueryRowxContext(ctx, query, claimID).Scan(&attempts)
+	return attempts, err
+}
+
+// CompleteClaim marks the claim verified, transfers the page to the claimant
+// and supersedes every other pending claim for the page, in one transaction.
+func (r *ClaimRepository) CompleteClaim(ctx context.Context, cl *domain.PageClaim) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	var previousOwner uuid.UUID
+	if err := tx.QueryRowxContext(ctx, `SELECT user_id FROM pages WHERE id = $1 FOR UPDATE`, cl.PageID).Scan(&previousOwner); err != nil {
+		return err
+	}
+
+	query := `UPDATE page_claims SET status = $1, previous_owner_id = $2, verified_at = NOW()
+			  WHERE id = $3 AND status = $4 RETURNING status, previous_owner_id, verified_at`
+	if err := tx.QueryRowxContext(ctx, query, domain.ClaimStatusVerified, previousOwner, cl.ID, domain.ClaimStatusPending).
+		Scan(&cl.Status, &cl.PreviousOwnerID, &cl.VerifiedAt); err != nil {
+		return err
+	}
+
+	if err := setOwner(ctx, tx, cl.PageID, cl.UserID, true); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetPageOwner assigns a page to a user, bypassing the claim flow, and
+// supersedes any pending claims for the page.
+func (r *ClaimRepository) SetPageOwner(ctx context.Context, pageID, userID uuid.UUID, verified bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	if err := setOwner(ctx, tx, pageID, userID, verified); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func setOwner(ctx context.Context, tx *sqlx.Tx, pageID, userID uuid.UUID, verified bool) error {
+	res, err := tx.ExecContext(ctx, `UPDATE pages SET user_id = $1, verified = $2 WHERE id = $3`, userID, verified, pageID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE page_claims SET status = $1 WHERE page_id = $2 AND status = $3`,
+		domain.ClaimStatusSuperseded, pageID, domain.ClaimStatusPending)
+	return err
+}
@@ -0,0 +1,202 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/pkg/database"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// GetImages retrieves a page's gallery in display order.
+func (r *PageRepository) GetImages(ctx context.Context, pageID uuid.UUID) ([]domain.PageImage, error) {
+	var images []domain.PageImage
+	query := `SELECT * FROM page_images WHERE page_id = $1 ORDER BY position ASC, created_at ASC`
+	err := r.db.SelectContext(ctx, &images, query, pageID)
+	return images, err
+}
+
+// AddImages appends images to a page's gallery. If the page has no cover
+// yet, the first new image becomes the cover. It returns
+// domain.ErrTooManyImages, adding nothing, if the page would end up with
+// more than maxCount images.
+func (r *PageRepository) AddImages(ctx context.Context, pageID uuid.UUID, urls []string, maxCount int) ([]domain.PageImage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	// Lock the page so concurrent uploads can't both pass the limit.
+	if err := lockPage(ctx, tx, pageID); err != nil {
+		return nil, err
+	}
+	images, err := appendImages(ctx, tx, pageID, urls, false, maxCount)
+	if err != nil {
+		return nil, err
+	}
+	return images, tx.Commit()
+}
+
+// appendImages adds images after the end of a locked page's gallery,
+// checking the limit. The first image becomes the cover if newCover is set
+// or the page has none yet.
+func appendImages(ctx context.Context, tx *sqlx.Tx, pageID uuid.UUID, urls []string, newCover bool, maxCount int) ([]domain.PageImage, error) {
+	var stats struct {
+		Count    int  `db:"count"`
+		Next     int  `db:"next"`
+		HasCover bool `db:"has_cover"`
+	}
+	query := `SELECT COUNT(*) AS count, COALESCE(MAX(position) + 1, 0) AS next, COALESCE(BOOL_OR(is_cover), FALSE) AS has_cover
+			  FROM page_images WHERE page_id = $1`
+	if err := tx.GetContext(ctx, &stats, query, pageID); err != nil {
+		return nil, err
+	}
+	if stats.Count+len(urls) > maxCount {
+		return nil, domain.ErrTooManyImages
+	}
+	if newCover && stats.HasCover {
+		if _, err := tx.ExecContext(ctx, `UPDATE page_images SET is_cover = FALSE WHERE page_id = $1 AND is_cover`, pageID); err != nil {
+			return nil, err
+		}
+		stats.HasCover = false
+	}
+	return insertImages(ctx, tx, pageID, urls, stats.Next, stats.HasCover)
+}
+
+// insertImages appends images to a gallery from position next on. Unless
+// the page has a cover already, the first image becomes its cover.
+func insertImages(ctx context.Context, tx *sqlx.Tx, pageID uuid.UUID, urls []string, next int, hasCover bool) ([]domain.PageImage, error) {
+	images := make([]domain.PageImage, len(urls))
+	for i, url := range urls {
+		img := domain.PageImage{PageID: pageID, URL: url, Position: next + i, IsCover: !hasCover && i == 0}
+		query := `INSERT INTO page_images (page_id, url, position, is_cover) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+		if err := tx.QueryRowxContext(ctx, query, img.PageID, img.URL, img.Position, img.IsCover).Scan(&img.ID, &img.CreatedAt); err != nil {
+			return nil, err
+		}
+		if img.IsCover {
+			if err := setCoverURL(ctx, tx, pageID, img.URL); err != nil {
+				return nil, err
+			}
+		}
+		images[i] = img
+	}
+	return images, nil
+}
+
+// DeleteImage removes an image from a page's gallery and returns it. If it
+// was the cover, the first remaining image becomes the cover.
+func (r *PageRepository) DeleteImage(ctx context.Context, pageID, imageID uuid.UUID) (*domain.PageImage, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	if err := lockPage(ctx, tx, pageID); err != nil {
+		return nil, err
+	}
+	var img domain.PageImage
+	if err := tx.GetContext(ctx, &img, `DELETE FROM page_images WHERE id = $1 AND page_id = $2 RETURNING *`, imageID, pageID); err != nil {
+		return nil, err
+	}
+	if img.IsCover {
+		var next domain.PageImage
+		err := tx.GetContext(ctx, &next, `SELECT * FROM page_images WHERE page_id = $1 ORDER BY position ASC, created_at ASC LIMIT 1`, pageID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			if err := setCoverURL(ctx, tx, pageID, ""); err != nil {
+				return nil, err
+			}
+		case err != nil:
+			return nil, err
+		default:
+			if _, err := tx.ExecContext(ctx, `UPDATE page_images SET is_cover = TRUE WHERE id = $1`, next.ID); err != nil {
+				return nil, err
+			}
+			if err := setCoverURL(ctx, tx, pageID, next.URL); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &img, tx.Commit()
+}
+
+// ReorderImages sets the display order of a page's gallery. imageIDs must
+// list every image of the page exactly once, or domain.ErrImageSetMismatch
+// is returned.
+func (r *PageRepository) ReorderImages(ctx context.Context, pageID uuid.UUID, imageIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	if err := lockPage(ctx, tx, pageID); err != nil {
+		return err
+	}
+	ids := make([]string, len(imageIDs))
+	for i, id := range imageIDs {
+		ids[i] = id.String()
+	}
+	query := `UPDATE page_images i SET position = o.ord - 1
+			  FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, ord)
+			  WHERE i.id = o.id AND i.page_id = $1`
+	res, err := tx.ExecContext(ctx, query, pageID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	var total int
+	if err := tx.GetContext(ctx, &total, `SELECT COUNT(*) FROM page_images WHERE page_id = $1`, pageID); err != nil {
+		return err
+	}
+	if int(updated) != len(imageIDs) || total != len(imageIDs) {
+		return domain.ErrImageSetMismatch
+	}
+	return tx.Commit()
+}
+
+// SetCoverImage makes an image the cover of its page.
+func (r *PageRepository) SetCoverImage(ctx context.Context, pageID, imageID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	if err := lockPage(ctx, tx, pageID); err != nil {
+		return err
+	}
+	var url string
+	if err := tx.GetContext(ctx, &url, `SELECT url FROM page_images WHERE id = $1 AND page_id = $2`, imageID, pageID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE page_images SET is_cover = FALSE WHERE page_id = $1 AND is_cover`, pageID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE page_images SET is_cover = TRUE WHERE id = $1`, imageID); err != nil {
+		return err
+	}
+	if err := setCoverURL(ctx, tx, pageID, url); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func lockPage(ctx context.Context, tx *sqlx.Tx, pageID uuid.UUID) error {
+	var id uuid.UUID
+	return tx.GetContext(ctx, &id, `SELECT id FROM pages WHERE id = $1 FOR UPDATE`, pageID)
+}
+
+func setCoverURL(ctx context.Context, tx *sqlx.Tx, pageID uuid.UUID, url string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE pages SET image_url = $1 WHERE id = $2`, url, pageID)
+	return err
+}
@@ -2,50 +2,95 @@ package postgres
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
 	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/pkg/database"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 // PageRepository provides a database implementation for page operations.
 type PageRepository struct {
-	db *sqlx.DB
-}
-
-// NewPageRepository creates a new PageRepository.
-func NewPageRepository(db *sqlx.DB) *PageRepository {
-	return &PageRepository{db: db}
+	db     *sqlx.DB
+	logger *slog.Logger
 }
 
-// CreatePage saves a new page to the database.
-func (r *PageRepository) CreatePage(ctx context.Context, p *domain.Page) error {
-	query := `INSERT INTO pages (user_id, title, description, image_url, link, has_issue)
-			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at, updated_at`
-	return r.db.QueryRowxContext(ctx, query, p.UserID, p.Title, p.Description, p.ImageURL, p.Link, p.HasIssue).Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt)
+// NewPageRepository creates a new PageRepository. A nil logger means
+// slog.Default().
+func NewPageRepository(db *sqlx.DB, logger *slog.Logger) *PageRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PageRepository{db: db, logger: logger}
 }
 
-// LinkPageToCategories associates a page with multiple categories in the join table.
-func (r *PageRepository) LinkPageToCategories(ctx context.Context, pageID uuid.UUID, categoryIDs []uuid.UUID) error {
+// CreatePage saves a new page with its gallery, the first image being the
+// cover, and its categories in one transaction, so that a failure leaves no
+// half-created page holding the handle. published_at is set if the page is
+// created published.
+func (r *PageRepository) CreatePage(ctx context.Context, p *domain.Page, imageURLs []string, categoryIDs []uuid.UUID) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer database.Rollback(ctx, r.logger, tx)
 
-	stmt, err := tx.PreparexContext(ctx, `INSERT INTO page_categories (page_id, category_id) VALUES ($1, $2)`)
+	query := `INSERT INTO pages (user_id, title, description, image_url, link, instagram_handle, has_issue, status, publish_at, published_at)
+			  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CASE WHEN $8 = 'published' THEN NOW() END)
+			  RETURNING id, created_at, updated_at, published_at`
+	err = tx.QueryRowxContext(ctx, query, p.UserID, p.Title, p.Description, p.ImageURL, p.Link, p.InstagramHandle, p.HasIssue, p.Status, p.PublishAt).
+		Scan(&p.ID, &p.CreatedAt, &p.UpdatedAt, &p.PublishedAt)
+	if err != nil {
+		return mapHandleConflict(err)
+	}
+	if p.Images, err = insertImages(ctx, tx, p.ID, imageURLs, 0, false); err != nil {
+		return err
+	}
+	if err := setCategories(ctx, tx, p.ID, categoryIDs); err != nil {
+		return err
+	}
+	// Adding the images bumped the version the insert started at.
+	if err := tx.GetContext(ctx, &p.Version, `SELECT version FROM pages WHERE id = $1`, p.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// setCategories replaces the categories of a page in the join table.
+// Deleted categories are skipped, and links to them are kept so that
+// restoring a category brings its pages back.
+func setCategories(ctx context.Context, tx *sqlx.Tx, pageID uuid.UUID, categoryIDs []uuid.UUID) error {
+	strIDs := make([]string, len(categoryIDs))
+	for i, id := range categoryIDs {
+		strIDs[i] = id.String()
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM page_categories pc USING categories c
+									  WHERE pc.page_id = $1 AND c.id = pc.category_id AND c.deleted_at IS NULL
+									  AND NOT (pc.category_id = ANY($2::uuid[]))`, pageID, pq.Array(strIDs)); err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PreparexContext(ctx, `INSERT INTO page_categories (page_id, category_id)
+									  SELECT $1, id FROM categories WHERE id = $2 AND deleted_at IS NULL
+									  ON CONFLICT DO NOTHING`)
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
 	defer stmt.Close()
 
 	for _, catID := range categoryIDs {
 		if _, err := stmt.ExecContext(ctx, pageID, catID); err != nil {
-			tx.Rollback()
 			return err
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
 
 // GetPageByID retrieves a single page by its ID.
@@ -56,25 +101,312 @@ func (r *PageRepository) GetPageByID(ctx context.Context, pageID uuid.UUID) (*do
 	return &p, err
 }
 
-// GetAllPages retrieves a paginated list of all pages.
-func (r *PageRepository) GetAllPages(ctx context.Context, limit, offset int) ([]domain.Page, error) {
+// GetPageByInstagramHandle retrieves the page registered for a normalized Instagram handle.
+func (r *PageRepository) GetPageByInstagramHandle(ctx context.Context, handle string) (*domain.Page, error) {
+	var p domain.Page
+	query := `SELECT * FROM pages WHERE instagram_handle = $1`
+	err := r.db.GetContext(ctx, &p, query, handle)
+	return &p, err
+}
+
+// GetPageForViewer retrieves a single page by its ID, flagging whether the
+// viewer has favorited it. viewerID may be uuid.Nil for anonymous requests.
+func (r *PageRepository) GetPageForViewer(ctx context.Context, pageID, viewerID uuid.UUID) (*domain.Page, error) {
+	var p domain.Page
+	query := `SELECT p.*, ` + favoritedBy("$2") + `, ` + tagNames + ` FROM pages p WHERE p.id = $1`
+	err := r.db.GetContext(ctx, &p, query, pageID, viewerID)
+	return &p, err
+}
+
+// GetAllPages retrieves a paginated list of published pages matching the
+// filter, most recently published first. A category filter also matches
+// pages in any of its descendant categories, and a tag filter also matches
+// pages with a tag it was merged into.
+func (r *PageRepository) GetAllPages(ctx context.Context, f domain.PageFilter) ([]domain.Page, error) {
 	var pages []domain.Page
-	query := `SELECT * FROM pages ORDER BY created_at DESC LIMIT $1 OFFSET $2`
-	err := r.db.SelectContext(ctx, &pages, query, limit, offset)
+	query := `WITH RECURSIVE subtree AS (
+				  SELECT id FROM categories WHERE id = $4 AND deleted_at IS NULL
+				  UNION
+				  SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id WHERE c.deleted_at IS NULL
+			  )
+			  SELECT p.*, ` + favoritedBy("$1") + `, ` + tagNames + ` FROM pages p
+			  WHERE p.status = 'published'
+				AND ($4::uuid IS NULL OR EXISTS (
+				  SELECT 1 FROM page_categories pc JOIN subtree s ON s.id = pc.category_id WHERE pc.page_id = p.id))
+				AND ($5 = '' OR EXISTS (
+				  SELECT 1 FROM page_tags pt JOIN tags t ON COALESCE(t.merged_into, t.id) = pt.tag_id
+				  WHERE pt.page_id = p.id AND t.name = $5))
+			  ORDER BY p.published_at DESC LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &pages, query, f.ViewerID, f.Limit, f.Offset, f.CategoryID, f.Tag)
 	return pages, err
 }
 
-// UpdatePage updates an existing page's details in the database.
-func (r *PageRepository) UpdatePage(ctx context.Context, p *domain.Page) error {
-	query := `UPDATE pages SET title = $1, description = $2, image_url = $3, link = $4, has_issue = $5, updated_at = NOW()
-			  WHERE id = $6 AND user_id = $7`
-	_, err := r.db.ExecContext(ctx, query, p.Title, p.Description, p.ImageURL, p.Link, p.HasIssue, p.ID, p.UserID)
+// GetPagesByIDs retrieves the given published pages for a viewer, in the
+// order of ids. Missing and unpublished IDs are skipped.
+func (r *PageRepository) GetPagesByIDs(ctx context.Context, ids []uuid.UUID, viewerID uuid.UUID) ([]domain.Page, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = id.String()
+	}
+
+	var found []domain.Page
+	query := `SELECT p.*, ` + favoritedBy("$2") + `, ` + tagNames + ` FROM pages p
+			  WHERE p.id = ANY($1::uuid[]) AND p.status = 'published'`
+	if err := r.db.SelectContext(ctx, &found, query, pq.Array(strIDs), viewerID); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]domain.Page, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+	pages := make([]domain.Page, 0, len(found))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			pages = append(pages, p)
+		}
+	}
+	return pages, nil
+}
+
+// UpdatePage updates an existing page's details and, in the same
+// transaction, the categories and new images in u, so that a failure leaves
+// the page as it was. published_at is set the first time the page is
+// published. Changing the Instagram handle expires the page's pending and
+// verified claims.
+//
+// The update only applies to a page owned by p.UserID and, if u.Version is
+// set, still at that version; otherwise it returns domain.ErrNotOwner or
+// domain.ErrVersionMismatch, or sql.ErrNoRows if the page is gone. It
+// returns domain.ErrTooManyImages if the gallery would end up with more than
+// u.MaxImages images. On success p.Version is the new version.
+func (r *PageRepository) UpdatePage(ctx context.Context, p *domain.Page, u domain.PageUpdate) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	var current struct {
+		UserID  uuid.UUID `db:"user_id"`
+		Version int64     `db:"version"`
+		Handle  *string   `db:"instagram_handle"`
+	}
+	if err := tx.GetContext(ctx, &current, `SELECT user_id, version, instagram_handle FROM pages WHERE id = $1 FOR UPDATE`, p.ID); err != nil {
+		return err
+	}
+	if current.UserID != p.UserID {
+		return domain.ErrNotOwner
+	}
+	if u.Version != nil && current.Version != *u.Version {
+		return domain.ErrVersionMismatch
+	}
+
+	query := `UPDATE pages SET title = $1, description = $2, link = $3, instagram_handle = $4, has_issue = $5,
+			  status = $6, publish_at = $7, published_at = CASE WHEN $6 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+			  verified = $8, updated_at = NOW()
+			  WHERE id = $9`
+	if _, err := tx.ExecContext(ctx, query, p.Title, p.Description, p.Link, p.InstagramHandle, p.HasIssue, p.Status, p.PublishAt, p.Verified, p.ID); err != nil {
+		return mapHandleConflict(err)
+	}
+
+	// Claims prove control of the account the page pointed at when they
+	// were made, so they don't carry over to another account.
+	if !sameHandle(current.Handle, p.InstagramHandle) {
+		if _, err := tx.ExecContext(ctx, `UPDATE page_claims SET status = $1 WHERE page_id = $2 AND status IN ($3, $4)`,
+			domain.ClaimStatusExpired, p.ID, domain.ClaimStatusPending, domain.ClaimStatusVerified); err != nil {
+			return err
+		}
+	}
+
+	if u.CategoryIDs != nil {
+		if err := setCategories(ctx, tx, p.ID, *u.CategoryIDs); err != nil {
+			return err
+		}
+	}
+	if len(u.ImageURLs) > 0 {
+		if _, err := appendImages(ctx, tx, p.ID, u.ImageURLs, u.NewCover, u.MaxImages); err != nil {
+			return err
+		}
+	}
+
+	// Categories and images bump the version through triggers.
+	if err := tx.GetContext(ctx, &p.Version, `SELECT version FROM pages WHERE id = $1`, p.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func sameHandle(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// DeletePage removes a page owned by userID from the database. With version
+// set, the page is only deleted while it is at that version; otherwise it
+// returns domain.ErrVersionMismatch. Without a version, it returns
+// sql.ErrNoRows if no page was deleted.
+func (r *PageRepository) DeletePage(ctx context.Context, pageID, userID uuid.UUID, version *int64) error {
+	query := `DELETE FROM pages WHERE id = $1 AND user_id = $2 AND ($3::bigint IS NULL OR version = $3)`
+	res, err := r.db.ExecContext(ctx, query, pageID, userID, version)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	switch {
+	case err != nil:
+		return err
+	case n > 0:
+		return nil
+	case version != nil:
+		return domain.ErrVersionMismatch
+	default:
+		return sql.ErrNoRows
+	}
+}
+
+// favoritedBy returns the select expression for Page.IsFavorited, where param
+// is the placeholder bound to the viewer's user ID.
+func favoritedBy(param string) string {
+	return `EXISTS (SELECT 1 FROM page_favorites f WHERE f.page_id = p.id AND f.user_id = ` + param + `) AS is_favorited`
+}
+
+// tagNames is a select-list expression for the names of p's tags.
+const tagNames = `ARRAY(SELECT t.name FROM page_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.page_id = p.id ORDER BY t.name) AS tags`
+
+// AddFavorite bookmarks a page for a user. Adding an existing favorite is a no-op.
+func (r *PageRepository) AddFavorite(ctx context.Context, userID, pageID uuid.UUID) error {
+	return r.changeFavorite(ctx, `INSERT INTO page_favorites (user_id, page_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+		`UPDATE pages SET favorite_count = favorite_count + 1 WHERE id = $1`, userID, pageID)
+}
+
+// RemoveFavorite removes a user's bookmark of a page, if any.
+func (r *PageRepository) RemoveFavorite(ctx context.Context, userID, pageID uuid.UUID) error {
+	return r.changeFavorite(ctx, `DELETE FROM page_favorites WHERE user_id = $1 AND page_id = $2`,
+		`UPDATE pages SET favorite_count = GREATEST(favorite_count - 1, 0) WHERE id = $1`, userID, pageID)
+}
+
+// changeFavorite runs a favorite insert or delete and, if it changed a row,
+// adjusts the page's favorite_count in the same transaction.
+func (r *PageRepository) changeFavorite(ctx context.Context, change, adjust string, userID, pageID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	res, err := tx.ExecContext(ctx, change, userID, pageID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return tx.Commit()
+	}
+	if _, err := tx.ExecContext(ctx, adjust, pageID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetFavoritePages retrieves a user's favorited published pages, most
+// recently favorited first, along with their total number.
+func (r *PageRepository) GetFavoritePages(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Page, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM page_favorites f JOIN pages p ON p.id = f.page_id
+				   WHERE f.user_id = $1 AND p.status = 'published'`
+	if err := r.db.GetContext(ctx, &total, countQuery, userID); err != nil {
+		return nil, 0, err
+	}
+
+	var pages []domain.Page
+	query := `SELECT p.*, TRUE AS is_favorited, ` + tagNames + ` FROM pages p
+			  JOIN page_favorites f ON f.page_id = p.id
+			  WHERE f.user_id = $1 AND p.status = 'published'
+			  ORDER BY f.created_at DESC LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &pages, query, userID, limit, offset)
+	return pages, total, err
+}
+
+// mapHandleConflict translates a unique violation on the Instagram handle
+// index into domain.ErrHandleTaken.
+func mapHandleConflict(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "idx_pages_instagram_handle" {
+		return domain.ErrHandleTaken
+	}
 	return err
 }
 
-// DeletePage removes a page from the database.
-func (r *PageRepository) DeletePage(ctx context.Context, pageID, userID uuid.UUID) error {
-	query := `DELETE FROM pages WHERE id = $1 AND user_id = $2`
-	_, err := r.db.ExecContext(ctx, query, pageID, userID)
+// GetPagesDueForLinkCheck retrieves pages whose link is due for a health check,
+// least recently scheduled first.
+func (r *PageRepository) GetPagesDueForLinkCheck(ctx context.Context, limit int) ([]domain.Page, error) {
+	var pages []domain.Page
+	query := `SELECT * FROM pages
+			  WHERE link IS NOT NULL AND link <> '' AND (link_next_check_at IS NULL OR link_next_check_at <= NOW())
+			  ORDER BY link_next_check_at NULLS FIRST LIMIT $1`
+	err := r.db.SelectContext(ctx, &pages, query, limit)
+	return pages, err
+}
+
+// RecordLinkCheck stores the outcome of a link health check. has_issue is only
+// ever set here, never cleared, so an owner-reported issue is not overwritten.
+func (r *PageRepository) RecordLinkCheck(ctx context.Context, res domain.LinkCheckResult) error {
+	query := `UPDATE pages SET link_checked_at = $1, link_status_code = $2, link_failure_streak = $3,
+			  link_next_check_at = $4, has_issue = has_issue OR $5
+			  WHERE id = $6`
+	var statusCode *int
+	if res.StatusCode != 0 {
+		statusCode = &res.StatusCode
+	}
+	_, err := r.db.ExecContext(ctx, query, res.CheckedAt, statusCode, res.FailureStreak, res.NextCheckAt, res.MarkIssue, res.PageID)
 	return err
 }
+
+// GetUserPages retrieves a user's pages in any of the given statuses, newest
+// first, along with their total number. viewerID personalizes is_favorited.
+func (r *PageRepository) GetUserPages(ctx context.Context, userID uuid.UUID, statuses []domain.PageStatus, viewerID uuid.UUID, limit, offset int) ([]domain.Page, int, error) {
+	strStatuses := make([]string, len(statuses))
+	for i, s := range statuses {
+		strStatuses[i] = string(s)
+	}
+
+	var total int
+	countQuery := `SELECT COUNT(*) FROM pages WHERE user_id = $1 AND status = ANY($2)`
+	if err := r.db.GetContext(ctx, &total, countQuery, userID, pq.Array(strStatuses)); err != nil {
+		return nil, 0, err
+	}
+
+	var pages []domain.Page
+	query := `SELECT p.*, ` + favoritedBy("$3") + `, ` + tagNames + ` FROM pages p
+			  WHERE p.user_id = $1 AND p.status = ANY($2)
+			  ORDER BY p.created_at DESC LIMIT $4 OFFSET $5`
+	err := r.db.SelectContext(ctx, &pages, query, userID, pq.Array(strStatuses), viewerID, limit, offset)
+	return pages, total, err
+}
+
+// PublishDuePages publishes up to limit scheduled pages whose publish_at has
+// passed and returns how many were published. Rows locked by a concurrent
+// call are skipped, so several instances can run it at once.
+func (r *PageRepository) PublishDuePages(ctx context.Context, limit int) (int, error) {
+	query := `UPDATE pages SET status = 'published', published_at = COALESCE(published_at, publish_at)
+			  WHERE id IN (
+				  SELECT id FROM pages
+				  WHERE status = 'scheduled' AND publish_at <= NOW()
+				  ORDER BY publish_at
+				  LIMIT $1
+				  FOR UPDATE SKIP LOCKED
+			  )`
+	res, err := r.db.ExecContext(ctx, query, limit)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// UpsertTranslation creates or replaces a page's translation for a locale.
+func (r *PageRepository) UpsertTranslation(ctx context.Context, t *domain.PageTranslation) error {
+	query := `INSERT INTO page_translations (page_id, locale, description)
+			  VALUES ($1, $2, $3)
+			  ON CONFLICT (page_id, locale) DO UPDATE SET description = EXCLUDED.description, updated_at = NOW()
+			  RETURNING updated_at`
+	return r.db.QueryRowxContext(ctx, query, t.PageID, t.Locale, t.Description).Scan(&t.UpdatedAt)
+}
+
+// DeleteTranslation removes a page's translation for a locale.
+func (r *PageRepository) DeleteTranslation(ctx context.Context, pageID uuid.UUID, locale string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM page_translations WHERE page_id = $1 AND locale = $2`, pageID, locale)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetTranslations retrieves every translation of a page.
+func (r *PageRepository) GetTranslations(ctx context.Context, pageID uuid.UUID) ([]domain.PageTranslation, error) {
+	var translations []domain.PageTranslation
+	query := `SELECT * FROM page_translations WHERE page_id = $1 ORDER BY locale`
+	err := r.db.SelectContext(ctx, &translations, query, pageID)
+	return translations, err
+}
+
+// GetTranslationsForLocale retrieves the translations of the given pages
+// into one locale, keyed by page ID.
+func (r *PageRepository) GetTranslationsForLocale(ctx context.Context, pageIDs []uuid.UUID, locale string) (map[uuid.UUID]domain.PageTranslation, error) {
+	byID := make(map[uuid.UUID]domain.PageTranslation)
+	if len(pageIDs) == 0 {
+		return byID, nil
+	}
+	ids := make([]string, len(pageIDs))
+	for i, id := range pageIDs {
+		ids[i] = id.String()
+	}
+
+	var translations []domain.PageTranslation
+	query := `SELECT * FROM page_translations WHERE page_id = ANY($1::uuid[]) AND locale = $2`
+	if err := r.db.SelectContext(ctx, &translations, query, pq.Array(ids), locale); err != nil {
+		return nil, err
+	}
+	for _, t := range translations {
+		byID[t.PageID] = t
+	}
+	return byID, nil
+}
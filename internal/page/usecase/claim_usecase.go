@@ -0,0 +1,166 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	claimTTL         = 24 * time.Hour
+	maxClaimAttempts = 10
+)
+
+// --- Interface Definitions for Dependencies ---
+type ClaimRepository interface {
+	CreateClaim(ctx context.Context, cl *domain.PageClaim) error
+	GetClaimByID(ctx context.Context, claimID uuid.UUID) (*domain.PageClaim, error)
+	IncrementClaimAttempts(ctx context.Context, claimID uuid.UUID) (int, error)
+	CompleteClaim(ctx context.Context, cl *domain.PageClaim) error
+	SetPageOwner(ctx context.Context, pageID, userID uuid.UUID, verified bool) error
+}
+
+// OwnershipVerifier checks whether the owner of an Instagram account has
+// placed a verification code in the account's bio.
+type OwnershipVerifier interface {
+	BioContains(ctx context.Context, handle, code string) (bool, error)
+}
+
+// --- Usecase Implementation ---
+type ClaimUsecase struct {
+	pageRepo  PageRepository
+	claimRepo ClaimRepository
+	verifier  OwnershipVerifier
+	logger    *slog.Logger
+}
+
+// NewClaimUsecase creates a ClaimUsecase. A nil logger means slog.Default().
+func NewClaimUsecase(pr PageRepository, cr ClaimRepository, v OwnershipVerifier, logger *slog.Logger) *ClaimUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &ClaimUsecase{pageRepo: pr, claimRepo: cr, verifier: v, logger: logger}
+}
+
+// --- Usecase Methods ---
+
+// StartClaim creates a pending claim with a fresh code the user must place
+// in the page's Instagram bio.
+func (uc *ClaimUsecase) StartClaim(ctx context.Context, pageID, userID uuid.UUID) (*domain.PageClaim, error) {
+	ctx, span := tracer.Start(ctx, "ClaimUsecase.StartClaim")
+	defer span.End()
+
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	if p.UserID == userID && p.Verified {
+		return nil, domain.ErrAlreadyOwner
+	}
+	if p.InstagramHandle == nil {
+		return nil, domain.ErrNoInstagramLink
+	}
+
+	code, err := generateClaimCode()
+	if err != nil {
+		return nil, err
+	}
+	claim := &domain.PageClaim{
+		PageID:    pageID,
+		UserID:    userID,
+		Code:      code,
+		ExpiresAt: time.Now().Add(claimTTL),
+	}
+	if err := uc.claimRepo.CreateClaim(ctx, claim); err != nil {
+		return nil, apperror.FromDB(err, "claim")
+	}
+	return claim, nil
+}
+
+// VerifyClaim checks the page's Instagram bio for the claim code and, if it
+// is present, transfers the page to the claimant and marks it verified.
+func (uc *ClaimUsecase) VerifyClaim(ctx context.Context, pageID, claimID, userID uuid.UUID) (*domain.PageClaim, error) {
+	ctx, span := tracer.Start(ctx, "ClaimUsecase.VerifyClaim")
+	defer span.End()
+
+	claim, err := uc.claimRepo.GetClaimByID(ctx, claimID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "claim")
+	}
+	if claim.PageID != pageID {
+		return nil, apperror.NotFound("claim")
+	}
+	if claim.UserID != userID {
+		return nil, apperror.Forbidden("claim belongs to another user")
+	}
+	if claim.Status != domain.ClaimStatusPending {
+		return nil, domain.ErrClaimNotPending
+	}
+	if time.Now().After(claim.ExpiresAt) {
+		return nil, domain.ErrClaimExpired
+	}
+
+	attempts, err := uc.claimRepo.IncrementClaimAttempts(ctx, claimID)
+	if err != nil {
+		return nil, err
+	}
+	if attempts > maxClaimAttempts {
+		return nil, domain.ErrTooManyAttempts
+	}
+
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	if p.InstagramHandle == nil {
+		return nil, domain.ErrNoInstagramLink
+	}
+
+	found, err := uc.verifier.BioContains(ctx, *p.InstagramHandle, claim.Code)
+	if err != nil {
+		return nil, fmt.Errorf("could not check instagram bio: %w", err)
+	}
+	if !found {
+		return nil, domain.ErrClaimCodeNotFound
+	}
+
+	if err := uc.claimRepo.CompleteClaim(ctx, claim); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrClaimNotPending
+		}
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "page claimed", "page_id", pageID, "claim_id", claimID, "user_id", userID)
+	return claim, nil
+}
+
+// AssignOwner lets an admin hand a page to a user without a claim.
+func (uc *ClaimUsecase) AssignOwner(ctx context.Context, pageID, userID uuid.UUID, verified bool) error {
+	ctx, span := tracer.Start(ctx, "ClaimUsecase.AssignOwner")
+	defer span.End()
+
+	if err := uc.claimRepo.SetPageOwner(ctx, pageID, userID, verified); err != nil {
+		return apperror.FromDB(err, "page")
+	}
+	uc.logger.InfoContext(ctx, "page owner assigned", "page_id", pageID, "user_id", userID, "verified", verified)
+	return nil
+}
+
+// generateClaimCode returns a short code that is unlikely to appear in a bio
+// by accident.
+func generateClaimCode() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "instawall-" + hex.EncodeToString(b), nil
+}
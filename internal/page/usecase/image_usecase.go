@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/google/uuid"
+)
+
+// --- Usecase Methods ---
+
+// GetImages returns a page's gallery in display order. viewerID is uuid.Nil
+// for anonymous requests.
+func (uc *PageUsecase) GetImages(ctx context.Context, pageID, viewerID uuid.UUID) ([]domain.PageImage, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetImages")
+	defer span.End()
+
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	if !visibleTo(p, viewerID) {
+		return nil, apperror.NotFound("page")
+	}
+	return uc.pageRepo.GetImages(ctx, pageID)
+}
+
+// AddImages uploads images and appends them to the gallery of a page owned
+// by userID.
+func (uc *PageUsecase) AddImages(ctx context.Context, pageID, userID uuid.UUID, uploads []ImageUpload) ([]domain.PageImage, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.AddImages")
+	defer span.End()
+
+	if err := uc.authorizeOwner(ctx, pageID, userID); err != nil {
+		return nil, err
+	}
+	if len(uploads) == 0 {
+		return nil, apperror.Invalid("invalid_images", "no images provided")
+	}
+	existing, err := uc.pageRepo.GetImages(ctx, pageID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkUploads(uploads, len(existing)); err != nil {
+		return nil, err
+	}
+	return uc.addImages(ctx, pageID, uploads)
+}
+
+// DeleteImage removes an image from the gallery of a page owned by userID.
+func (uc *PageUsecase) DeleteImage(ctx context.Context, pageID, imageID, userID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PageUsecase.DeleteImage")
+	defer span.End()
+
+	if err := uc.authorizeOwner(ctx, pageID, userID); err != nil {
+		return err
+	}
+	img, err := uc.pageRepo.DeleteImage(ctx, pageID, imageID)
+	if err != nil {
+		return apperror.FromDB(err, "image")
+	}
+	if err := uc.fileStore.DeleteFile(ctx, img.URL); err != nil {
+		uc.logger.WarnContext(ctx, "delete image file", "url", img.URL, "err", err)
+	}
+	return nil
+}
+
+// ReorderImages sets the display order of the gallery of a page owned by
+// userID. imageIDs must list every image of the page exactly once.
+func (uc *PageUsecase) ReorderImages(ctx context.Context, pageID, userID uuid.UUID, imageIDs []uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PageUsecase.ReorderImages")
+	defer span.End()
+
+	if err := uc.authorizeOwner(ctx, pageID, userID); err != nil {
+		return err
+	}
+	err := uc.pageRepo.ReorderImages(ctx, pageID, imageIDs)
+	if errors.Is(err, domain.ErrImageSetMismatch) {
+		return apperror.Invalid("invalid_order", err.Error()).Wrap(err)
+	}
+	return err
+}
+
+// SetCoverImage makes an image the cover of a page owned by userID.
+func (uc *PageUsecase) SetCoverImage(ctx context.Context, pageID, imageID, userID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PageUsecase.SetCoverImage")
+	defer span.End()
+
+	if err := uc.authorizeOwner(ctx, pageID, userID); err != nil {
+		return err
+	}
+	return apperror.FromDB(uc.pageRepo.SetCoverImage(ctx, pageID, imageID), "image")
+}
+
+func (uc *PageUsecase) authorizeOwner(ctx context.Context, pageID, userID uuid.UUID) error {
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return apperror.FromDB(err, "page")
+	}
+	if p.UserID != userID {
+		return apperror.Forbidden("user does not own this page")
+	}
+	return nil
+}
+
+// checkUploads validates uploads against the configured limits for a page
+// that already has existing images.
+func (uc *PageUsecase) checkUploads(uploads []ImageUpload, existing int) error {
+	if uc.imageLimits.MaxCount > 0 && existing+len(uploads) > uc.imageLimits.MaxCount {
+		return tooManyImages(uc.imageLimits.MaxCount)
+	}
+	for _, u := range uploads {
+		if uc.imageLimits.MaxSize > 0 && u.Size > uc.imageLimits.MaxSize {
+			return apperror.Invalid("image_too_large", fmt.Sprintf("%s exceeds the %d byte limit", u.Name, uc.imageLimits.MaxSize))
+		}
+	}
+	return nil
+}
+
+// addImages uploads and attaches images, removing the uploaded files again
+// if they cannot be attached.
+func (uc *PageUsecase) addImages(ctx context.Context, pageID uuid.UUID, uploads []ImageUpload) ([]domain.PageImage, error) {
+	urls, err := uc.upload(ctx, uploads)
+	if err != nil {
+		return nil, err
+	}
+	images, err := uc.pageRepo.AddImages(ctx, pageID, urls, uc.imageLimits.MaxCount)
+	if err != nil {
+		uc.discard(ctx, urls)
+		if errors.Is(err, domain.ErrTooManyImages) {
+			return nil, tooManyImages(uc.imageLimits.MaxCount)
+		}
+		return nil, err
+	}
+	return images, nil
+}
+
+func tooManyImages(max int) error {
+	return apperror.Invalid("too_many_images", fmt.Sprintf("a page can have at most %d images", max))
+}
+
+func (uc *PageUsecase) upload(ctx context.Context, uploads []ImageUpload) ([]string, error) {
+	urls := make([]string, 0, len(uploads))
+	for _, u := range uploads {
+		url, err := uc.fileStore.UploadFile(ctx, u.File, u.Size, u.Name)
+		if err != nil {
+			uc.discard(ctx, urls)
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, nil
+}
+
+// discard removes uploaded files that were not attached to a page.
+func (uc *PageUsecase) discard(ctx context.Context, urls []string) {
+	for _, url := range urls {
+		if err := uc.fileStore.DeleteFile(ctx, url); err != nil {
+			uc.logger.WarnContext(ctx, "delete image file", "url", url, "err", err)
+		}
+	}
+}
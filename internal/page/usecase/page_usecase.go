@@ -2,34 +2,80 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
 
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/metrics"
 	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/pkg/i18n"
+	"github.com/cavidyrm/instawall/pkg/instagram"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
 )
 
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/internal/page/usecase")
+
 // --- Interface Definitions for Dependencies ---
 type PageRepository interface {
-	CreatePage(ctx context.Context, p *domain.Page) error
+	CreatePage(ctx context.Context, p *domain.Page, imageURLs []string, categoryIDs []uuid.UUID) error
 	GetPageByID(ctx context.Context, pageID uuid.UUID) (*domain.Page, error)
-	GetAllPages(ctx context.Context, limit, offset int) ([]domain.Page, error)
-	UpdatePage(ctx context.Context, p *domain.Page) error
-	DeletePage(ctx context.Context, pageID, userID uuid.UUID) error
-	LinkPageToCategories(ctx context.Context, pageID uuid.UUID, categoryIDs []uuid.UUID) error
+	GetPageByInstagramHandle(ctx context.Context, handle string) (*domain.Page, error)
+	GetPageForViewer(ctx context.Context, pageID, viewerID uuid.UUID) (*domain.Page, error)
+	GetAllPages(ctx context.Context, f domain.PageFilter) ([]domain.Page, error)
+	UpdatePage(ctx context.Context, p *domain.Page, u domain.PageUpdate) error
+	DeletePage(ctx context.Context, pageID, userID uuid.UUID, version *int64) error
+	AddFavorite(ctx context.Context, userID, pageID uuid.UUID) error
+	RemoveFavorite(ctx context.Context, userID, pageID uuid.UUID) error
+	GetFavoritePages(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Page, int, error)
+	GetUserPages(ctx context.Context, userID uuid.UUID, statuses []domain.PageStatus, viewerID uuid.UUID, limit, offset int) ([]domain.Page, int, error)
+	UpsertTranslation(ctx context.Context, t *domain.PageTranslation) error
+	DeleteTranslation(ctx context.Context, pageID uuid.UUID, locale string) error
+	GetTranslations(ctx context.Context, pageID uuid.UUID) ([]domain.PageTranslation, error)
+	GetTranslationsForLocale(ctx context.Context, pageIDs []uuid.UUID, locale string) (map[uuid.UUID]domain.PageTranslation, error)
+	GetImages(ctx context.Context, pageID uuid.UUID) ([]domain.PageImage, error)
+	AddImages(ctx context.Context, pageID uuid.UUID, urls []string, maxCount int) ([]domain.PageImage, error)
+	DeleteImage(ctx context.Context, pageID, imageID uuid.UUID) (*domain.PageImage, error)
+	ReorderImages(ctx context.Context, pageID uuid.UUID, imageIDs []uuid.UUID) error
+	SetCoverImage(ctx context.Context, pageID, imageID uuid.UUID) error
 }
 type FileStore interface {
 	UploadFile(ctx context.Context, file io.Reader, fileSize int64, originalFilename string) (string, error)
+	DeleteFile(ctx context.Context, url string) error
+}
+
+// PromotionSource supplies sponsored pages to interleave into listings.
+type PromotionSource interface {
+	PromotedPages(ctx context.Context, categoryID *uuid.UUID, tag string, viewerID uuid.UUID, n int) ([]domain.Page, error)
+	RecordImpressions(ctx context.Context, promotionIDs []uuid.UUID) error
 }
 
 // --- Usecase Implementation ---
 type PageUsecase struct {
-	pageRepo  PageRepository
-	fileStore FileStore
+	pageRepo    PageRepository
+	fileStore   FileStore
+	promotions  PromotionSource
+	promoSlots  []int
+	imageLimits domain.ImageLimits
+	logger      *slog.Logger
 }
 
-func NewPageUsecase(pr PageRepository, fs FileStore) *PageUsecase {
-	return &PageUsecase{pageRepo: pr, fileStore: fs}
+// NewPageUsecase creates a PageUsecase. promoSlots are the zero-based
+// positions in each listing response where sponsored pages are placed; ps
+// may be nil to disable promotions. A nil logger means slog.Default().
+func NewPageUsecase(pr PageRepository, fs FileStore, ps PromotionSource, promoSlots []int, limits domain.ImageLimits, logger *slog.Logger) *PageUsecase {
+	slots := append([]int(nil), promoSlots...)
+	sort.Ints(slots)
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PageUsecase{pageRepo: pr, fileStore: fs, promotions: ps, promoSlots: slots, imageLimits: limits, logger: logger}
 }
 
 // --- Input DTOs ---
@@ -40,110 +86,540 @@ type CreatePageInput struct {
 	Link        string
 	HasIssue    bool
 	CategoryIDs []uuid.UUID
-	ImageFile   io.Reader
-	ImageSize   int64
-	ImageName   string
+	Images      []ImageUpload     // at least one; the first becomes the cover
+	Status      domain.PageStatus // Optional: published, or scheduled if PublishAt is set
+	PublishAt   *time.Time        // required for scheduled pages
 }
 type UpdatePageInput struct {
 	PageID      uuid.UUID
 	UserID      uuid.UUID
-	Title       string
-	Description string
-	Link        string
-	HasIssue    bool
-	CategoryIDs []uuid.UUID
-	ImageFile   io.Reader // Optional: nil if not updating image
-	ImageSize   int64
-	ImageName   string
+	Title       *string           // Optional: nil fields are left unchanged
+	Description *string           // Optional
+	Link        *string           // Optional
+	HasIssue    *bool             // Optional
+	CategoryIDs *[]uuid.UUID      // Optional: an empty list removes every category
+	Cover       *ImageUpload      // Optional: added to the gallery as the new cover
+	Images      []ImageUpload     // Optional: added to the end of the gallery
+	Status      domain.PageStatus // Optional: empty with a nil PublishAt keeps the current status
+	PublishAt   *time.Time
+	Version     *int64 // Optional: the update fails unless the page is at this version
+}
+type ImageUpload struct {
+	File io.Reader
+	Size int64
+	Name string
 }
 
 // --- Usecase Methods ---
 
 func (uc *PageUsecase) CreatePage(ctx context.Context, input CreatePageInput) (*domain.Page, error) {
-	imageURL, err := uc.fileStore.UploadFile(ctx, input.ImageFile, input.ImageSize, input.ImageName)
+	ctx, span := tracer.Start(ctx, "PageUsecase.CreatePage")
+	defer span.End()
+
+	handle, err := instagram.ParseHandle(input.Link)
 	if err != nil {
+		return nil, apperror.Invalid("invalid_link", err.Error()).Wrap(err)
+	}
+	if err := uc.ensureHandleAvailable(ctx, handle, uuid.Nil); err != nil {
 		return nil, err
 	}
 
-	newPage := &domain.Page{
-		UserID:      input.UserID,
-		Title:       input.Title,
-		Description: input.Description,
-		Link:        input.Link,
-		HasIssue:    input.HasIssue,
-		ImageURL:    imageURL,
+	status, publishAt, err := resolveStatus(input.Status, input.PublishAt, time.Now())
+	if err != nil {
+		return nil, err
 	}
-
-	if err := uc.pageRepo.CreatePage(ctx, newPage); err != nil {
+	if len(input.Images) == 0 {
+		return nil, apperror.Invalid("invalid_images", "at least one image is required")
+	}
+	if err := uc.checkUploads(input.Images, 0); err != nil {
+		return nil, err
+	}
+	urls, err := uc.upload(ctx, input.Images)
+	if err != nil {
 		return nil, err
 	}
 
-	if len(input.CategoryIDs) > 0 {
-		if err := uc.pageRepo.LinkPageToCategories(ctx, newPage.ID, input.CategoryIDs); err != nil {
-			return nil, err
-		}
+	newPage := &domain.Page{
+		UserID:          input.UserID,
+		Title:           input.Title,
+		Description:     input.Description,
+		Link:            instagram.ProfileURL(handle),
+		InstagramHandle: &handle,
+		HasIssue:        input.HasIssue,
+		ImageURL:        urls[0],
+		Status:          status,
+		PublishAt:       publishAt,
 	}
 
+	if err := uc.pageRepo.CreatePage(ctx, newPage, urls, input.CategoryIDs); err != nil {
+		uc.discard(ctx, urls)
+		if errors.Is(err, domain.ErrHandleTaken) {
+			return nil, uc.duplicateError(ctx, handle)
+		}
+		return nil, apperror.FromDB(err, "page")
+	}
+	metrics.PagesCreated.Inc()
 	return newPage, nil
 }
 
-func (uc *PageUsecase) GetPage(ctx context.Context, pageID uuid.UUID) (*domain.Page, error) {
-	return uc.pageRepo.GetPageByID(ctx, pageID)
+// GetPage retrieves a page translated into locale. viewerID is uuid.Nil for
+// anonymous requests. Unpublished pages are only visible to their owner.
+func (uc *PageUsecase) GetPage(ctx context.Context, pageID, viewerID uuid.UUID, locale string) (*domain.Page, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetPage")
+	defer span.End()
+
+	p, err := uc.pageRepo.GetPageForViewer(ctx, pageID, viewerID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	if !visibleTo(p, viewerID) {
+		return nil, apperror.NotFound("page")
+	}
+	if p.Images, err = uc.pageRepo.GetImages(ctx, pageID); err != nil {
+		return nil, err
+	}
+	pages := []domain.Page{*p}
+	if err := uc.localize(ctx, pages, locale); err != nil {
+		return nil, err
+	}
+	return &pages[0], nil
+}
+
+// GetAllPages lists pages matching the filter, translated into locale, with
+// active promotions placed at the configured slots.
+func (uc *PageUsecase) GetAllPages(ctx context.Context, f domain.PageFilter, locale string) ([]domain.Page, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetAllPages")
+	defer span.End()
+
+	pages, err := uc.listPages(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return pages, uc.localize(ctx, pages, locale)
 }
 
-func (uc *PageUsecase) GetAllPages(ctx context.Context, limit, offset int) ([]domain.Page, error) {
-	return uc.pageRepo.GetAllPages(ctx, limit, offset)
+func (uc *PageUsecase) listPages(ctx context.Context, f domain.PageFilter) ([]domain.Page, error) {
+	pages, err := uc.pageRepo.GetAllPages(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	if uc.promotions == nil {
+		return pages, nil
+	}
+
+	n := 0
+	for _, slot := range uc.promoSlots {
+		if slot <= len(pages)+n {
+			n++
+		}
+	}
+	if n == 0 {
+		return pages, nil
+	}
+	promoted, err := uc.promotions.PromotedPages(ctx, f.CategoryID, f.Tag, f.ViewerID, n)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "load promoted pages", "err", err)
+		return pages, nil
+	}
+
+	pages, shown := interleave(pages, promoted, uc.promoSlots)
+	if len(shown) > 0 {
+		if err := uc.promotions.RecordImpressions(ctx, shown); err != nil {
+			uc.logger.WarnContext(ctx, "record promotion impressions", "err", err)
+		}
+	}
+	return pages, nil
 }
 
 func (uc *PageUsecase) UpdatePage(ctx context.Context, input UpdatePageInput) (*domain.Page, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.UpdatePage")
+	defer span.End()
+
 	// First, get the existing page to ensure it exists and to have its current data.
 	existingPage, err := uc.pageRepo.GetPageByID(ctx, input.PageID)
 	if err != nil {
-		return nil, fmt.Errorf("page not found")
+		return nil, apperror.FromDB(err, "page")
 	}
 
 	// Authorization check: Ensure the user owns the page.
 	if existingPage.UserID != input.UserID {
-		return nil, fmt.Errorf("forbidden: user does not own this page")
+		return nil, domain.ErrNotOwner
+	}
+	if input.Version != nil && *input.Version != existingPage.Version {
+		return nil, domain.ErrVersionMismatch
 	}
 
-	// If a new image file is provided, upload it and update the URL.
-	// Otherwise, keep the existing image URL.
-	imageURL := existingPage.ImageURL
-	if input.ImageFile != nil {
-		newImageURL, err := uc.fileStore.UploadFile(ctx, input.ImageFile, input.ImageSize, input.ImageName)
+	// Start from the current values and apply the fields that were sent.
+	pageToUpdate := *existingPage
+	if input.Title != nil {
+		pageToUpdate.Title = *input.Title
+	}
+	if input.Description != nil {
+		pageToUpdate.Description = *input.Description
+	}
+	if input.HasIssue != nil {
+		pageToUpdate.HasIssue = *input.HasIssue
+	}
+	if input.Link != nil {
+		handle, err := instagram.ParseHandle(*input.Link)
 		if err != nil {
+			return nil, apperror.Invalid("invalid_link", err.Error()).Wrap(err)
+		}
+		if err := uc.ensureHandleAvailable(ctx, handle, existingPage.ID); err != nil {
 			return nil, err
 		}
-		imageURL = newImageURL
+		pageToUpdate.Link = instagram.ProfileURL(handle)
+		pageToUpdate.InstagramHandle = &handle
+		if existingPage.InstagramHandle == nil || *existingPage.InstagramHandle != handle {
+			// Verification only proved control of the previous account.
+			pageToUpdate.Verified = false
+		}
 	}
 
-	// Update the page object with new data.
-	pageToUpdate := &domain.Page{
-		ID:          input.PageID,
-		UserID:      input.UserID,
-		Title:       input.Title,
-		Description: input.Description,
-		Link:        input.Link,
-		HasIssue:    input.HasIssue,
-		ImageURL:    imageURL,
+	status, publishAt := existingPage.Status, existingPage.PublishAt
+	if input.Status != "" || input.PublishAt != nil {
+		if status, publishAt, err = resolveStatus(input.Status, input.PublishAt, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
+	// New images are added to the gallery; a new cover is added first.
+	uploads := input.Images
+	if input.Cover != nil {
+		uploads = append([]ImageUpload{*input.Cover}, uploads...)
+	}
+	if len(uploads) > 0 {
+		existing, err := uc.pageRepo.GetImages(ctx, input.PageID)
+		if err != nil {
+			return nil, err
+		}
+		if err := uc.checkUploads(uploads, len(existing)); err != nil {
+			return nil, err
+		}
 	}
 
-	// Save the updated page to the database.
-	if err := uc.pageRepo.UpdatePage(ctx, pageToUpdate); err != nil {
+	pageToUpdate.Status = status
+	pageToUpdate.PublishAt = publishAt
+
+	// Files are uploaded first; the page, its categories and the new images
+	// are then saved in one transaction.
+	urls, err := uc.upload(ctx, uploads)
+	if err != nil {
 		return nil, err
 	}
+	update := domain.PageUpdate{
+		Version:     input.Version,
+		CategoryIDs: input.CategoryIDs,
+		ImageURLs:   urls,
+		NewCover:    input.Cover != nil,
+		MaxImages:   uc.imageLimits.MaxCount,
+	}
+	if err := uc.pageRepo.UpdatePage(ctx, &pageToUpdate, update); err != nil {
+		uc.discard(ctx, urls)
+		switch {
+		case errors.Is(err, domain.ErrHandleTaken) && pageToUpdate.InstagramHandle != nil:
+			return nil, uc.duplicateError(ctx, *pageToUpdate.InstagramHandle)
+		case errors.Is(err, domain.ErrTooManyImages):
+			return nil, tooManyImages(uc.imageLimits.MaxCount)
+		}
+		return nil, apperror.FromDB(err, "page")
+	}
 
-	// Update category links.
-	if err := uc.pageRepo.LinkPageToCategories(ctx, pageToUpdate.ID, input.CategoryIDs); err != nil {
+	updatedPage, err := uc.pageRepo.GetPageByID(ctx, input.PageID)
+	if err != nil {
+		return nil, err
+	}
+	if updatedPage.Images, err = uc.pageRepo.GetImages(ctx, input.PageID); err != nil {
 		return nil, err
 	}
+	return updatedPage, nil
+}
+
+// DeletePage deletes a page owned by userID. With version set, the page is
+// only deleted while it is at that version.
+func (uc *PageUsecase) DeletePage(ctx context.Context, pageID, userID uuid.UUID, version *int64) error {
+	ctx, span := tracer.Start(ctx, "PageUsecase.DeletePage")
+	defer span.End()
 
-	return pageToUpdate, nil
+	// Tell a page that is missing or not the user's apart from a stale
+	// version; the delete itself enforces all three again.
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return apperror.FromDB(err, "page")
+	}
+	if p.UserID != userID {
+		return domain.ErrNotOwner
+	}
+	if version != nil && p.Version != *version {
+		return domain.ErrVersionMismatch
+	}
+	return apperror.FromDB(uc.pageRepo.DeletePage(ctx, pageID, userID, version), "page")
 }
 
-func (uc *PageUsecase) DeletePage(ctx context.Context, pageID, userID uuid.UUID) error {
-	// In a real-world app, you might get the page first to ensure ownership
-	// before deleting, but the SQL query also enforces this.
-	return uc.pageRepo.DeletePage(ctx, pageID, userID)
+// ensureHandleAvailable returns a duplicate page conflict if a page other
+// than selfID is already registered for the given Instagram handle.
+func (uc *PageUsecase) ensureHandleAvailable(ctx context.Context, handle string, selfID uuid.UUID) error {
+	existing, err := uc.pageRepo.GetPageByInstagramHandle(ctx, handle)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID == selfID {
+		return nil
+	}
+	return duplicatePage(existing.ID, handle)
+}
+
+// duplicateError builds a duplicate page conflict after an insert or update
+// lost the race for a handle to a concurrent request.
+func (uc *PageUsecase) duplicateError(ctx context.Context, handle string) error {
+	existing, err := uc.pageRepo.GetPageByInstagramHandle(ctx, handle)
+	if err != nil {
+		return apperror.Conflict("duplicate_page", domain.ErrHandleTaken.Error()).Wrap(domain.ErrHandleTaken)
+	}
+	return duplicatePage(existing.ID, handle)
+}
+
+// duplicatePage reports that a page was already submitted for handle. The
+// response carries the existing page's ID.
+func duplicatePage(existingID uuid.UUID, handle string) error {
+	dupErr := &domain.DuplicatePageError{ExistingPageID: existingID, Handle: handle}
+	return apperror.Conflict("duplicate_page", dupErr.Error()).With("page_id", existingID).Wrap(dupErr)
+}
+
+func (uc *PageUsecase) AddFavorite(ctx context.Context, userID, pageID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PageUsecase.AddFavorite")
+	defer span.End()
+
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return apperror.FromDB(err, "page")
+	}
+	if p.Status != domain.PageStatusPublished {
+		return apperror.NotFound("page")
+	}
+	return uc.pageRepo.AddFavorite(ctx, userID, pageID)
+}
+
+func (uc *PageUsecase) RemoveFavorite(ctx context.Context, userID, pageID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PageUsecase.RemoveFavorite")
+	defer span.End()
+
+	return uc.pageRepo.RemoveFavorite(ctx, userID, pageID)
+}
+
+// GetFavorites returns a page of the user's favorites, translated into
+// locale, and the total count.
+func (uc *PageUsecase) GetFavorites(ctx context.Context, userID uuid.UUID, limit, offset int, locale string) ([]domain.Page, int, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetFavorites")
+	defer span.End()
+
+	pages, total, err := uc.pageRepo.GetFavoritePages(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pages, total, uc.localize(ctx, pages, locale)
+}
+
+// GetDrafts returns a page of the user's draft and scheduled pages,
+// translated into locale, and the total count.
+func (uc *PageUsecase) GetDrafts(ctx context.Context, userID uuid.UUID, limit, offset int, locale string) ([]domain.Page, int, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetDrafts")
+	defer span.End()
+
+	statuses := []domain.PageStatus{domain.PageStatusDraft, domain.PageStatusScheduled}
+	return uc.userPages(ctx, userID, statuses, userID, limit, offset, locale)
+}
+
+// GetMyPages returns a page of the pages owned by userID, translated into
+// locale, and the total count. An empty status selects pages in any status.
+func (uc *PageUsecase) GetMyPages(ctx context.Context, userID uuid.UUID, status domain.PageStatus, limit, offset int, locale string) ([]domain.Page, int, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetMyPages")
+	defer span.End()
+
+	statuses := []domain.PageStatus{domain.PageStatusDraft, domain.PageStatusScheduled, domain.PageStatusPublished}
+	if status != "" {
+		if !status.Valid() {
+			return nil, 0, apperror.Invalid("invalid_status", fmt.Sprintf("unknown status %q", status))
+		}
+		statuses = []domain.PageStatus{status}
+	}
+	return uc.userPages(ctx, userID, statuses, userID, limit, offset, locale)
+}
+
+// GetUserPages returns a page of the published pages owned by ownerID as
+// seen by viewerID, translated into locale, and the total count.
+func (uc *PageUsecase) GetUserPages(ctx context.Context, ownerID, viewerID uuid.UUID, limit, offset int, locale string) ([]domain.Page, int, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetUserPages")
+	defer span.End()
+
+	statuses := []domain.PageStatus{domain.PageStatusPublished}
+	return uc.userPages(ctx, ownerID, statuses, viewerID, limit, offset, locale)
+}
+
+func (uc *PageUsecase) userPages(ctx context.Context, ownerID uuid.UUID, statuses []domain.PageStatus, viewerID uuid.UUID, limit, offset int, locale string) ([]domain.Page, int, error) {
+	pages, total, err := uc.pageRepo.GetUserPages(ctx, ownerID, statuses, viewerID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	return pages, total, uc.localize(ctx, pages, locale)
+}
+
+// SetTranslation creates or replaces a page's description in a locale.
+func (uc *PageUsecase) SetTranslation(ctx context.Context, pageID uuid.UUID, locale, description string) (*domain.PageTranslation, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.SetTranslation")
+	defer span.End()
+
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return nil, apperror.Invalid("invalid_locale", err.Error())
+	}
+	if description == "" {
+		return nil, apperror.Invalid("invalid_translation", "description is required")
+	}
+	if _, err := uc.pageRepo.GetPageByID(ctx, pageID); err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	t := &domain.PageTranslation{PageID: pageID, Locale: locale, Description: description}
+	if err := uc.pageRepo.UpsertTranslation(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetTranslations lists every translation of a page.
+func (uc *PageUsecase) GetTranslations(ctx context.Context, pageID uuid.UUID) ([]domain.PageTranslation, error) {
+	ctx, span := tracer.Start(ctx, "PageUsecase.GetTranslations")
+	defer span.End()
+
+	if _, err := uc.pageRepo.GetPageByID(ctx, pageID); err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	return uc.pageRepo.GetTranslations(ctx, pageID)
+}
+
+// DeleteTranslation removes a page's translation into a locale.
+func (uc *PageUsecase) DeleteTranslation(ctx context.Context, pageID uuid.UUID, locale string) error {
+	ctx, span := tracer.Start(ctx, "PageUsecase.DeleteTranslation")
+	defer span.End()
+
+	locale, err := i18n.Normalize(locale)
+	if err != nil {
+		return apperror.Invalid("invalid_locale", err.Error())
+	}
+	return apperror.FromDB(uc.pageRepo.DeleteTranslation(ctx, pageID, locale), "translation")
+}
+
+// localize translates page descriptions into locale where a translation
+// exists. An empty locale leaves the pages untouched.
+func (uc *PageUsecase) localize(ctx context.Context, pages []domain.Page, locale string) error {
+	if locale == "" || len(pages) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(pages))
+	for i, p := range pages {
+		ids[i] = p.ID
+	}
+	translations, err := uc.pageRepo.GetTranslationsForLocale(ctx, ids, locale)
+	if err != nil {
+		return err
+	}
+	domain.Localize(pages, translations)
+	return nil
+}
+
+// resolveStatus validates a requested status and publish time. An empty
+// status means published, or scheduled when publishAt is set. Only
+// scheduled pages keep a publish time, which must be in the future.
+func resolveStatus(status domain.PageStatus, publishAt *time.Time, now time.Time) (domain.PageStatus, *time.Time, error) {
+	if status == "" {
+		status = domain.PageStatusPublished
+		if publishAt != nil {
+			status = domain.PageStatusScheduled
+		}
+	}
+	if !status.Valid() {
+		return "", nil, apperror.Invalid("invalid_status", fmt.Sprintf("unknown status %q", status))
+	}
+	if status != domain.PageStatusScheduled {
+		return status, nil, nil
+	}
+	if publishAt == nil {
+		return "", nil, apperror.Invalid("invalid_publish_at", "publish_at is required for scheduled pages")
+	}
+	if !publishAt.After(now) {
+		return "", nil, apperror.Invalid("invalid_publish_at", "publish_at must be in the future")
+	}
+	return status, publishAt, nil
+}
+
+// visibleTo reports whether viewerID may see p: published pages are public,
+// others are only visible to their owner.
+func visibleTo(p *domain.Page, viewerID uuid.UUID) bool {
+	return p.Status == domain.PageStatusPublished || (viewerID != uuid.Nil && p.UserID == viewerID)
+}
+
+// interleave places promoted pages at the given sorted slots, dropping
+// organic duplicates of the promoted pages it places, and returns the IDs
+// of the promotions that made it into the result. A promotion whose slot
+// lies past the end of the page is left out and its organic copy kept.
+func interleave(organic, promoted []domain.Page, slots []int) ([]domain.Page, []uuid.UUID) {
+	var unique []domain.Page
+	seen := make(map[uuid.UUID]bool, len(promoted))
+	for _, p := range promoted {
+		if !seen[p.ID] {
+			seen[p.ID] = true
+			unique = append(unique, p)
+		}
+	}
+
+	// Placing more promotions can only shorten the organic list, so take
+	// the largest count whose slots all fit once its duplicates are gone.
+	n := min(len(unique), len(slots))
+	var rest []domain.Page
+	for ; n >= 0; n-- {
+		rest = withoutPages(organic, unique[:n])
+		if fits(slots[:n], len(rest)) {
+			break
+		}
+	}
+
+	result := make([]domain.Page, 0, len(rest)+n)
+	result = append(result, rest...)
+	var shown []uuid.UUID
+	for i, p := range unique[:n] {
+		result = slices.Insert(result, slots[i], p)
+		if p.PromotionID != nil {
+			shown = append(shown, *p.PromotionID)
+		}
+	}
+	return result, shown
+}
+
+// fits reports whether promotions inserted one by one at slots all land
+// within a list that starts with n entries.
+func fits(slots []int, n int) bool {
+	for i, slot := range slots {
+		if slot < 0 || slot > n+i {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutPages returns the pages not among drop.
+func withoutPages(pages, drop []domain.Page) []domain.Page {
+	ids := make(map[uuid.UUID]bool, len(drop))
+	for _, p := range drop {
+		ids[p.ID] = true
+	}
+	kept := make([]domain.Page, 0, len(pages))
+	for _, p := range pages {
+		if !ids[p.ID] {
+			kept = append(kept, p)
+		}
+	}
+	return kept
 }
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/google/uuid"
+)
+
+// fakePageRepo holds a single page. Methods the tests don't exercise panic
+// through the nil embedded interface.
+type fakePageRepo struct {
+	PageRepository
+	page      domain.Page
+	updated   *domain.Page
+	update    domain.PageUpdate
+	updates   int
+	updateErr error
+	deleted   bool
+	deleteErr error
+}
+
+func (r *fakePageRepo) GetPageByID(_ context.Context, pageID uuid.UUID) (*domain.Page, error) {
+	if pageID != r.page.ID {
+		return nil, sql.ErrNoRows
+	}
+	p := r.page
+	return &p, nil
+}
+
+func (r *fakePageRepo) GetPageByInstagramHandle(_ context.Context, handle string) (*domain.Page, error) {
+	if r.page.InstagramHandle == nil || *r.page.InstagramHandle != handle {
+		return nil, sql.ErrNoRows
+	}
+	p := r.page
+	return &p, nil
+}
+
+func (r *fakePageRepo) UpdatePage(_ context.Context, p *domain.Page, u domain.PageUpdate) error {
+	r.updates++
+	r.update = u
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	updated := *p
+	r.updated = &updated
+	r.page = updated
+	return nil
+}
+
+func (r *fakePageRepo) DeletePage(_ context.Context, pageID, userID uuid.UUID, version *int64) error {
+	if r.deleteErr != nil {
+		return r.deleteErr
+	}
+	r.deleted = true
+	return nil
+}
+
+func (r *fakePageRepo) GetImages(context.Context, uuid.UUID) ([]domain.PageImage, error) {
+	return nil, nil
+}
+
+func newVerifiedPage(handle string) domain.Page {
+	return domain.Page{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		Title:           "Page",
+		Link:            "https://www.instagram.com/" + handle + "/",
+		InstagramHandle: &handle,
+		Verified:        true,
+		Status:          domain.PageStatusPublished,
+		Version:         3,
+	}
+}
+
+func TestUpdatePageHandleChangeDropsVerification(t *testing.T) {
+	repo := &fakePageRepo{page: newVerifiedPage("old.account")}
+	uc := NewPageUsecase(repo, nil, nil, nil, domain.ImageLimits{}, nil)
+
+	link := "https://instagram.com/new.account"
+	got, err := uc.UpdatePage(context.Background(), UpdatePageInput{PageID: repo.page.ID, UserID: repo.page.UserID, Link: &link})
+	if err != nil {
+		t.Fatalf("UpdatePage: %v", err)
+	}
+	if repo.updated.Verified {
+		t.Error("page stayed verified after moving to another Instagram account")
+	}
+	if *repo.updated.InstagramHandle != "new.account" {
+		t.Errorf("handle = %q, want new.account", *repo.updated.InstagramHandle)
+	}
+	if got.Verified {
+		t.Error("returned page is still verified")
+	}
+}
+
+func TestUpdatePageKeepsVerification(t *testing.T) {
+	title := "Renamed"
+	sameLink := "https://www.instagram.com/old.account"
+	tests := []struct {
+		name  string
+		input UpdatePageInput
+	}{
+		{"other fields", UpdatePageInput{Title: &title}},
+		{"same handle", UpdatePageInput{Link: &sameLink}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePageRepo{page: newVerifiedPage("old.account")}
+			uc := NewPageUsecase(repo, nil, nil, nil, domain.ImageLimits{}, nil)
+
+			input := tt.input
+			input.PageID, input.UserID = repo.page.ID, repo.page.UserID
+			if _, err := uc.UpdatePage(context.Background(), input); err != nil {
+				t.Fatalf("UpdatePage: %v", err)
+			}
+			if !repo.updated.Verified {
+				t.Error("page lost its verification")
+			}
+		})
+	}
+}
+
+// fakeFileStore hands out a URL per upload and records deletions.
+type fakeFileStore struct {
+	uploaded, deleted []string
+}
+
+func (fs *fakeFileStore) UploadFile(_ context.Context, _ io.Reader, _ int64, name string) (string, error) {
+	url := "https://files.example.com/" + name
+	fs.uploaded = append(fs.uploaded, url)
+	return url, nil
+}
+
+func (fs *fakeFileStore) DeleteFile(_ context.Context, url string) error {
+	fs.deleted = append(fs.deleted, url)
+	return nil
+}
+
+func TestUpdatePageSavesEverythingAtOnce(t *testing.T) {
+	repo := &fakePageRepo{page: newVerifiedPage("account")}
+	fs := &fakeFileStore{}
+	uc := NewPageUsecase(repo, fs, nil, nil, domain.ImageLimits{MaxCount: 5}, nil)
+
+	categories := []uuid.UUID{uuid.New()}
+	input := UpdatePageInput{
+		PageID:      repo.page.ID,
+		UserID:      repo.page.UserID,
+		CategoryIDs: &categories,
+		Cover:       &ImageUpload{File: strings.NewReader("x"), Size: 1, Name: "cover.jpg"},
+		Images:      []ImageUpload{{File: strings.NewReader("y"), Size: 1, Name: "more.jpg"}},
+	}
+	if _, err := uc.UpdatePage(context.Background(), input); err != nil {
+		t.Fatalf("UpdatePage: %v", err)
+	}
+	if repo.updates != 1 {
+		t.Fatalf("UpdatePage called %d times on the repository, want 1", repo.updates)
+	}
+	u := repo.update
+	if u.Version != nil {
+		t.Errorf("Version = %d without If-Match, want none", *u.Version)
+	}
+	if u.CategoryIDs == nil || len(*u.CategoryIDs) != 1 || (*u.CategoryIDs)[0] != categories[0] {
+		t.Errorf("CategoryIDs = %v, want %v", u.CategoryIDs, categories)
+	}
+	want := []string{"https://files.example.com/cover.jpg", "https://files.example.com/more.jpg"}
+	if strings.Join(u.ImageURLs, " ") != strings.Join(want, " ") || !u.NewCover {
+		t.Errorf("ImageURLs, NewCover = %v, %t, want %v, true", u.ImageURLs, u.NewCover, want)
+	}
+}
+
+func TestUpdatePageFailureDiscardsUploads(t *testing.T) {
+	repo := &fakePageRepo{page: newVerifiedPage("account"), updateErr: domain.ErrVersionMismatch}
+	fs := &fakeFileStore{}
+	uc := NewPageUsecase(repo, fs, nil, nil, domain.ImageLimits{MaxCount: 5}, nil)
+
+	version := repo.page.Version
+	input := UpdatePageInput{
+		PageID:  repo.page.ID,
+		UserID:  repo.page.UserID,
+		Version: &version,
+		Images:  []ImageUpload{{File: strings.NewReader("y"), Size: 1, Name: "more.jpg"}},
+	}
+	if _, err := uc.UpdatePage(context.Background(), input); !errors.Is(err, domain.ErrVersionMismatch) {
+		t.Fatalf("UpdatePage error = %v, want ErrVersionMismatch", err)
+	}
+	if repo.update.Version == nil || *repo.update.Version != version {
+		t.Errorf("Version = %v, want %d from If-Match", repo.update.Version, version)
+	}
+	if len(fs.deleted) != 1 || fs.deleted[0] != fs.uploaded[0] {
+		t.Errorf("deleted %v, want the upload %v", fs.deleted, fs.uploaded)
+	}
+}
+
+func TestDeletePage(t *testing.T) {
+	stale, current := int64(2), int64(3)
+	tests := []struct {
+		name      string
+		otherPage bool // delete a page that doesn't exist
+		otherUser bool // delete as a user who doesn't own the page
+		version   *int64
+		deleteErr error
+		wantKind  apperror.Kind
+		wantErr   error
+	}{
+		{name: "own page"},
+		{name: "own page at its version", version: &current},
+		{name: "missing page", otherPage: true, wantKind: apperror.KindNotFound},
+		{name: "missing page with a version", otherPage: true, version: &current, wantKind: apperror.KindNotFound},
+		{name: "another user's page", otherUser: true, wantKind: apperror.KindForbidden},
+		{name: "another user's page with a version", otherUser: true, version: &current, wantKind: apperror.KindForbidden},
+		{name: "stale version", version: &stale, wantErr: domain.ErrVersionMismatch},
+		{name: "deleted concurrently", deleteErr: sql.ErrNoRows, wantKind: apperror.KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakePageRepo{page: newVerifiedPage("account"), deleteErr: tt.deleteErr}
+			uc := NewPageUsecase(repo, nil, nil, nil, domain.ImageLimits{}, nil)
+			pageID, userID := repo.page.ID, repo.page.UserID
+			if tt.otherPage {
+				pageID = uuid.New()
+			}
+			if tt.otherUser {
+				userID = uuid.New()
+			}
+
+			err := uc.DeletePage(context.Background(), pageID, userID, tt.version)
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantKind != 0:
+				if !apperror.Is(err, tt.wantKind) {
+					t.Fatalf("error = %v, want kind %d", err, tt.wantKind)
+				}
+			case err != nil:
+				t.Fatalf("DeletePage: %v", err)
+			}
+			wantDeleted := err == nil
+			if repo.deleted != wantDeleted {
+				t.Errorf("deleted = %t, want %t", repo.deleted, wantDeleted)
+			}
+		})
+	}
+}
+
+// testPages returns a page per name with IDs derived from the names, so
+// the same name always yields the same page. Names starting with "+" are
+// promoted and carry a promotion ID.
+func testPages(names ...string) []domain.Page {
+	pages := make([]domain.Page, len(names))
+	for i, name := range names {
+		id := strings.TrimPrefix(name, "+")
+		pages[i] = domain.Page{ID: uuid.NewSHA1(uuid.NameSpaceOID, []byte(id)), Title: id}
+		if id != name {
+			promotionID := uuid.NewSHA1(uuid.NameSpaceURL, []byte(id))
+			pages[i].PromotionID = &promotionID
+		}
+	}
+	return pages
+}
+
+func TestInterleave(t *testing.T) {
+	tests := []struct {
+		name              string
+		organic, promoted []string
+		slots             []int
+		want              string
+		shown             int
+	}{
+		{
+			name:     "promotions at their slots",
+			organic:  []string{"a", "b", "c", "d"},
+			promoted: []string{"+x", "+y"},
+			slots:    []int{1, 3},
+			want:     "a x b y c d",
+			shown:    2,
+		},
+		{
+			name:     "organic duplicate of a placed promotion dropped",
+			organic:  []string{"a", "x", "b", "c"},
+			promoted: []string{"+x"},
+			slots:    []int{0},
+			want:     "x a b c",
+			shown:    1,
+		},
+		{
+			name:     "slot past a short page keeps the organic copy",
+			organic:  []string{"a", "x"},
+			promoted: []string{"+y", "+x"},
+			slots:    []int{1, 5},
+			want:     "a y x",
+			shown:    1,
+		},
+		{
+			name:     "dropping a duplicate pushes a slot off the page",
+			organic:  []string{"a", "x"},
+			promoted: []string{"+x"},
+			slots:    []int{2},
+			want:     "a x",
+		},
+		{
+			name:     "duplicate promotions placed once",
+			organic:  []string{"a", "b", "c"},
+			promoted: []string{"+x", "+x", "+y"},
+			slots:    []int{0, 2},
+			want:     "x a y b c",
+			shown:    2,
+		},
+		{
+			name:     "more promotions than slots",
+			organic:  []string{"a", "b"},
+			promoted: []string{"+x", "+y"},
+			slots:    []int{1},
+			want:     "a x b",
+			shown:    1,
+		},
+		{
+			name:     "no organic pages",
+			promoted: []string{"+x", "+y"},
+			slots:    []int{0, 1},
+			want:     "x y",
+			shown:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, shown := interleave(testPages(tt.organic...), testPages(tt.promoted...), tt.slots)
+			titles := make([]string, len(got))
+			for i, p := range got {
+				titles[i] = p.Title
+			}
+			if s := strings.Join(titles, " "); s != tt.want {
+				t.Errorf("pages = %q, want %q", s, tt.want)
+			}
+			if len(shown) != tt.shown {
+				t.Errorf("shown %d promotions, want %d", len(shown), tt.shown)
+			}
+		})
+	}
+}
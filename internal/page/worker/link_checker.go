@@ -0,0 +1,289 @@
+package worker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cavidyrm/instawall/config"
+	"github.com/cavidyrm/instawall/internal/page/domain"
+)
+
+// LinkCheckRepository defines the storage needed by the link checker.
+type LinkCheckRepository interface {
+	GetPagesDueForLinkCheck(ctx context.Context, limit int) ([]domain.Page, error)
+	RecordLinkCheck(ctx context.Context, res domain.LinkCheckResult) error
+}
+
+// LinkChecker periodically requests each page's link and records whether the
+// Instagram account still exists.
+type LinkChecker struct {
+	repo   LinkCheckRepository
+	client *http.Client
+	cfg    config.LinkCheckerConfig
+	hosts  *hostLimiter
+	logger *slog.Logger
+}
+
+// NewLinkChecker creates a new LinkChecker. If client is nil a client with the
+// configured timeout is used, and a nil logger means slog.Default().
+func NewLinkChecker(repo LinkCheckRepository, client *http.Client, cfg config.LinkCheckerConfig, logger *slog.Logger) *LinkChecker {
+	if client == nil {
+		client = &http.Client{Timeout: cfg.Timeout}
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Minute
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 3
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &LinkChecker{
+		repo:   repo,
+		client: client,
+		cfg:    cfg,
+		hosts:  newHostLimiter(cfg.PerHostInterval, cfg.MaxBackoff),
+		logger: logger.With("worker", "link_checker"),
+	}
+}
+
+// Run checks due pages every poll interval until ctx is cancelled.
+func (lc *LinkChecker) Run(ctx context.Context) {
+	ticker := time.NewTicker(lc.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := lc.CheckDue(ctx); err != nil && ctx.Err() == nil {
+			lc.logger.ErrorContext(ctx, "check due links", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDue checks one batch of due pages and returns how many were checked.
+func (lc *LinkChecker) CheckDue(ctx context.Context) (int, error) {
+	pages, err := lc.repo.GetPagesDueForLinkCheck(ctx, lc.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sem := make(chan struct{}, lc.cfg.Concurrency)
+	var wg sync.WaitGroup
+	for _, p := range pages {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return 0, ctx.Err()
+		}
+		wg.Add(1)
+		go func(p domain.Page) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			res, ok := lc.check(ctx, p)
+			if !ok {
+				return
+			}
+			if err := lc.repo.RecordLinkCheck(ctx, res); err != nil && ctx.Err() == nil {
+				lc.logger.ErrorContext(ctx, "record link check", "page_id", p.ID, "err", err)
+			}
+		}(p)
+	}
+	wg.Wait()
+	return len(pages), nil
+}
+
+// check requests a single page link. It reports false if ctx was cancelled
+// before the check could complete.
+func (lc *LinkChecker) check(ctx context.Context, p domain.Page) (domain.LinkCheckResult, bool) {
+	u, err := url.Parse(p.Link)
+	if err != nil || u.Host == "" {
+		return lc.result(p, 0, outcomeFailure, 0), true
+	}
+	if err := lc.hosts.wait(ctx, u.Host); err != nil {
+		return domain.LinkCheckResult{}, false
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.Link, nil)
+	if err != nil {
+		return lc.result(p, 0, outcomeFailure, 0), true
+	}
+	if lc.cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", lc.cfg.UserAgent)
+	}
+
+	resp, err := lc.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return domain.LinkCheckResult{}, false
+		}
+		return lc.result(p, 0, outcomeFailure, 0), true
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	outcome := classify(resp.StatusCode)
+	if outcome == outcomeInconclusive {
+		lc.hosts.penalize(u.Host, retryAfter(resp))
+	} else {
+		lc.hosts.reward(u.Host)
+	}
+	return lc.result(p, resp.StatusCode, outcome, retryAfter(resp)), true
+}
+
+type outcome int
+
+const (
+	outcomeHealthy outcome = iota
+	outcomeFailure
+	outcomeInconclusive
+)
+
+// classify decides what a response status says about the linked account.
+// Rate limiting and server errors say nothing about the account itself.
+func classify(status int) outcome {
+	switch {
+	case status < 400:
+		return outcomeHealthy
+	case status == http.StatusTooManyRequests || status >= 500:
+		return outcomeInconclusive
+	default:
+		return outcomeFailure
+	}
+}
+
+// result builds the stored result and schedules the next check, backing off
+// exponentially while a link keeps failing.
+func (lc *LinkChecker) result(p domain.Page, status int, o outcome, wait time.Duration) domain.LinkCheckResult {
+	now := time.Now()
+	res := domain.LinkCheckResult{
+		PageID:        p.ID,
+		StatusCode:    status,
+		FailureStreak: p.LinkFailureStreak,
+		CheckedAt:     now,
+	}
+	switch o {
+	case outcomeHealthy:
+		res.FailureStreak = 0
+		res.NextCheckAt = now.Add(lc.cfg.RecheckInterval)
+	case outcomeFailure:
+		res.FailureStreak++
+		res.MarkIssue = res.FailureStreak >= lc.cfg.FailureThreshold
+		res.NextCheckAt = now.Add(backoff(lc.cfg.RetryInterval, res.FailureStreak, lc.cfg.MaxBackoff))
+	case outcomeInconclusive:
+		res.NextCheckAt = now.Add(max(wait, lc.cfg.RetryInterval))
+	}
+	return res
+}
+
+// backoff returns base doubled for every failure after the first, capped at limit.
+func backoff(base time.Duration, failures int, limit time.Duration) time.Duration {
+	d := base
+	for i := 1; i < failures && (limit <= 0 || d < limit); i++ {
+		d *= 2
+	}
+	if limit > 0 && d > limit {
+		d = limit
+	}
+	return d
+}
+
+// retryAfter parses a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs < 0 {
+		return 0
+	}
+	return time.Duration(secs) * time.Second
+}
+
+// hostLimiter spaces out requests to the same host and backs off hosts that
+// rate limit or fail.
+type hostLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	limit    time.Duration
+	hosts    map[string]*hostState
+}
+
+type hostState struct {
+	next    time.Time
+	penalty time.Duration
+}
+
+func newHostLimiter(interval, limit time.Duration) *hostLimiter {
+	return &hostLimiter{interval: interval, limit: limit, hosts: make(map[string]*hostState)}
+}
+
+// wait reserves the next request slot for host and sleeps until it arrives.
+func (h *hostLimiter) wait(ctx context.Context, host string) error {
+	h.mu.Lock()
+	st, ok := h.hosts[host]
+	if !ok {
+		st = &hostState{}
+		h.hosts[host] = st
+	}
+	now := time.Now()
+	slot := st.next
+	if slot.Before(now) {
+		slot = now
+	}
+	st.next = slot.Add(h.interval + st.penalty)
+	h.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// penalize doubles the host's extra delay, or applies retryAfter if longer.
+func (h *hostLimiter) penalize(host string, retryAfter time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	st := h.hosts[host]
+	if st == nil {
+		return
+	}
+	st.penalty = max(st.penalty*2, h.interval, time.Second)
+	if h.limit > 0 && st.penalty > h.limit {
+		st.penalty = h.limit
+	}
+	if until := time.Now().Add(retryAfter); until.After(st.next) {
+		st.next = until
+	}
+}
+
+// reward clears any backoff applied to the host.
+func (h *hostLimiter) reward(host string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if st := h.hosts[host]; st != nil {
+		st.penalty = 0
+	}
+}
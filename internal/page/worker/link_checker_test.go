@@ -0,0 +1,205 @@
+package worker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cavidyrm/instawall/config"
+	"github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/google/uuid"
+)
+
+type fakeLinkCheckRepo struct {
+	mu      sync.Mutex
+	pages   []domain.Page
+	results []domain.LinkCheckResult
+}
+
+func (r *fakeLinkCheckRepo) GetPagesDueForLinkCheck(_ context.Context, limit int) ([]domain.Page, error) {
+	return r.pages[:min(limit, len(r.pages))], nil
+}
+
+func (r *fakeLinkCheckRepo) RecordLinkCheck(_ context.Context, res domain.LinkCheckResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.results = append(r.results, res)
+	return nil
+}
+
+func testLinkCheckerConfig() config.LinkCheckerConfig {
+	return config.LinkCheckerConfig{
+		Enabled:          true,
+		PollInterval:     time.Minute,
+		RecheckInterval:  24 * time.Hour,
+		RetryInterval:    time.Hour,
+		MaxBackoff:       6 * time.Hour,
+		Timeout:          100 * time.Millisecond,
+		Concurrency:      2,
+		BatchSize:        10,
+		FailureThreshold: 3,
+	}
+}
+
+func TestCheckDue(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		streak  int // failure streak before the check
+
+		wantStatus int
+		wantStreak int
+		wantIssue  bool
+		wantNext   time.Duration
+	}{
+		{
+			name:       "healthy",
+			handler:    func(w http.ResponseWriter, r *http.Request) {},
+			streak:     2,
+			wantStatus: http.StatusOK,
+			wantStreak: 0,
+			wantNext:   24 * time.Hour,
+		},
+		{
+			name:       "first failure",
+			handler:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			wantStatus: http.StatusNotFound,
+			wantStreak: 1,
+			wantNext:   time.Hour,
+		},
+		{
+			name:       "failure reaching the threshold",
+			handler:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			streak:     2,
+			wantStatus: http.StatusNotFound,
+			wantStreak: 3,
+			wantIssue:  true,
+			wantNext:   4 * time.Hour,
+		},
+		{
+			name:       "backoff is capped",
+			handler:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNotFound) },
+			streak:     5,
+			wantStatus: http.StatusNotFound,
+			wantStreak: 6,
+			wantIssue:  true,
+			wantNext:   6 * time.Hour,
+		},
+		{
+			name:       "server error is inconclusive",
+			handler:    func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) },
+			streak:     1,
+			wantStatus: http.StatusServiceUnavailable,
+			wantStreak: 1,
+			wantNext:   time.Hour,
+		},
+		{
+			name: "rate limit honours Retry-After",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "7200")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantStatus: http.StatusTooManyRequests,
+			wantStreak: 0,
+			wantNext:   2 * time.Hour,
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(time.Second):
+				}
+			},
+			streak:     2,
+			wantStatus: 0,
+			wantStreak: 3,
+			wantIssue:  true,
+			wantNext:   4 * time.Hour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(tt.handler)
+			defer srv.Close()
+
+			page := domain.Page{ID: uuid.New(), Link: srv.URL + "/account", LinkFailureStreak: tt.streak}
+			repo := &fakeLinkCheckRepo{pages: []domain.Page{page}}
+			lc := NewLinkChecker(repo, nil, testLinkCheckerConfig(), nil)
+
+			n, err := lc.CheckDue(context.Background())
+			if err != nil {
+				t.Fatalf("CheckDue: %v", err)
+			}
+			if n != 1 || len(repo.results) != 1 {
+				t.Fatalf("checked %d pages and recorded %d results, want 1", n, len(repo.results))
+			}
+			res := repo.results[0]
+			if res.PageID != page.ID {
+				t.Errorf("PageID = %v, want %v", res.PageID, page.ID)
+			}
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if res.FailureStreak != tt.wantStreak {
+				t.Errorf("FailureStreak = %d, want %d", res.FailureStreak, tt.wantStreak)
+			}
+			if res.MarkIssue != tt.wantIssue {
+				t.Errorf("MarkIssue = %v, want %v", res.MarkIssue, tt.wantIssue)
+			}
+			if got := res.NextCheckAt.Sub(res.CheckedAt); got != tt.wantNext {
+				t.Errorf("next check after %v, want %v", got, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestCheckDueSendsUserAgent(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+	}))
+	defer srv.Close()
+
+	cfg := testLinkCheckerConfig()
+	cfg.UserAgent = "instawall-test"
+	repo := &fakeLinkCheckRepo{pages: []domain.Page{{ID: uuid.New(), Link: srv.URL}}}
+	if _, err := NewLinkChecker(repo, nil, cfg, nil).CheckDue(context.Background()); err != nil {
+		t.Fatalf("CheckDue: %v", err)
+	}
+	if got != cfg.UserAgent {
+		t.Errorf("User-Agent = %q, want %q", got, cfg.UserAgent)
+	}
+}
+
+func TestCheckDueInvalidLink(t *testing.T) {
+	repo := &fakeLinkCheckRepo{pages: []domain.Page{{ID: uuid.New(), Link: "not a url"}}}
+	if _, err := NewLinkChecker(repo, nil, testLinkCheckerConfig(), nil).CheckDue(context.Background()); err != nil {
+		t.Fatalf("CheckDue: %v", err)
+	}
+	if res := repo.results[0]; res.StatusCode != 0 || res.FailureStreak != 1 {
+		t.Errorf("got status %d and streak %d, want a failure without a status", res.StatusCode, res.FailureStreak)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		limit    time.Duration
+		want     time.Duration
+	}{
+		{1, 0, time.Minute},
+		{2, 0, 2 * time.Minute},
+		{4, 0, 8 * time.Minute},
+		{4, 5 * time.Minute, 5 * time.Minute},
+		{100, time.Hour, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := backoff(time.Minute, tt.failures, tt.limit); got != tt.want {
+			t.Errorf("backoff(1m, %d, %v) = %v, want %v", tt.failures, tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// ScheduledPublisher publishes scheduled pages whose publish time has passed.
+type ScheduledPublisher interface {
+	PublishDuePages(ctx context.Context, limit int) (int, error)
+}
+
+// Publisher periodically publishes due scheduled pages. Several replicas may
+// run it at once; each due page is published by exactly one of them.
+type Publisher struct {
+	pages     ScheduledPublisher
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewPublisher creates a new Publisher. A nil logger means slog.Default().
+func NewPublisher(pages ScheduledPublisher, interval time.Duration, batchSize int, logger *slog.Logger) *Publisher {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if batchSize < 1 {
+		batchSize = 100
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Publisher{pages: pages, interval: interval, batchSize: batchSize, logger: logger.With("worker", "page_publisher")}
+}
+
+// Run publishes due pages immediately and then every interval until ctx is
+// cancelled.
+func (p *Publisher) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		n, err := p.PublishDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			p.logger.ErrorContext(ctx, "publish scheduled pages", "err", err)
+		} else if n > 0 {
+			p.logger.InfoContext(ctx, "published scheduled pages", "count", n)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishDue publishes due pages in batches until none are left and returns
+// how many were published.
+func (p *Publisher) PublishDue(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		n, err := p.pages.PublishDuePages(ctx, p.batchSize)
+		total += n
+		if err != nil || n < p.batchSize {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
+	"github.com/cavidyrm/instawall/internal/promotion/usecase"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PromotionHandler struct {
+	promotionUsecase *usecase.PromotionUsecase
+}
+
+func RegisterPromotionHandlers(e *echo.Echo, uc *usecase.PromotionUsecase) {
+	h := &PromotionHandler{promotionUsecase: uc}
+
+	// Page owners request and manage promotions of their pages
+	promoGroup := e.Group("/promotions")
+	promoGroup.Use(appMiddleware.JWTAuthMiddleware)
+	promoGroup.POST("", h.CreatePromotion)
+	promoGroup.GET("", h.GetMyPromotions)
+	promoGroup.DELETE("/:id", h.CancelPromotion)
+
+	// Admin-only review
+	adminGroup := e.Group("/admin/promotions")
+	adminGroup.Use(appMiddleware.JWTAuthMiddleware, appMiddleware.AdminOnlyMiddleware)
+	adminGroup.GET("", h.GetPromotions)
+	adminGroup.POST("/:id/approve", h.ApprovePromotion)
+	adminGroup.POST("/:id/reject", h.RejectPromotion)
+}
+
+// Request/Response Structs
+type CreatePromotionRequest struct {
+	PageID           string    `json:"page_id" validate:"required,uuid"`
+	CategoryIDs      []string  `json:"category_ids" validate:"omitempty,uuid_list"`
+	StartsAt         time.Time `json:"starts_at" validate:"required"`
+	EndsAt           time.Time `json:"ends_at" validate:"required"`
+	PaymentReference string    `json:"payment_reference" validate:"max=255"`
+}
+type ApprovePromotionRequest struct {
+	Priority *int `json:"priority" validate:"omitempty,gte=0"`
+}
+
+// --- Handler Methods ---
+
+func (h *PromotionHandler) CreatePromotion(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	var req CreatePromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	pageID, err := uuid.Parse(req.PageID)
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+	categoryIDs := make([]uuid.UUID, len(req.CategoryIDs))
+	for i, s := range req.CategoryIDs {
+		if categoryIDs[i], err = uuid.Parse(s); err != nil {
+			return apperror.Validation("invalid category_ids format")
+		}
+	}
+
+	promo, err := h.promotionUsecase.CreatePromotion(c.Request().Context(), usecase.CreatePromotionInput{
+		UserID:           userID,
+		PageID:           pageID,
+		CategoryIDs:      categoryIDs,
+		StartsAt:         req.StartsAt,
+		EndsAt:           req.EndsAt,
+		PaymentReference: req.PaymentReference,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, promo)
+}
+
+func (h *PromotionHandler) GetMyPromotions(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	limit, offset := pagination(c)
+
+	promos, err := h.promotionUsecase.GetMyPromotions(c.Request().Context(), userID, limit, offset)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, promos)
+}
+
+func (h *PromotionHandler) CancelPromotion(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid promotion ID")
+	}
+
+	if err := h.promotionUsecase.CancelPromotion(c.Request().Context(), id, userID); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *PromotionHandler) GetPromotions(c echo.Context) error {
+	limit, offset := pagination(c)
+
+	promos, err := h.promotionUsecase.GetPromotions(c.Request().Context(), c.QueryParam("status"), limit, offset)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, promos)
+}
+
+func (h *PromotionHandler) ApprovePromotion(c echo.Context) error {
+	adminID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid promotion ID")
+	}
+	var req ApprovePromotionRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	promo, err := h.promotionUsecase.ApprovePromotion(c.Request().Context(), id, adminID, req.Priority)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, promo)
+}
+
+func (h *PromotionHandler) RejectPromotion(c echo.Context) error {
+	adminID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid promotion ID")
+	}
+
+	promo, err := h.promotionUsecase.RejectPromotion(c.Request().Context(), id, adminID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, promo)
+}
+
+// pagination reads limit and offset query parameters with defaults.
+func pagination(c echo.Context) (limit, offset int) {
+	limit, _ = strconv.Atoi(c.QueryParam("limit"))
+	offset, _ = strconv.Atoi(c.QueryParam("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Promotion statuses.
+const (
+	StatusPending   = "pending"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+	StatusCancelled = "cancelled"
+)
+
+// Promotion is a paid placement of a page in page listings.
+type Promotion struct {
+	ID               uuid.UUID   `db:"id"`
+	PageID           uuid.UUID   `db:"page_id"`
+	RequestedBy      uuid.UUID   `db:"requested_by"`
+	CategoryIDs      []uuid.UUID `db:"-"` // empty means every listing
+	StartsAt         time.Time   `db:"starts_at"`
+	EndsAt           time.Time   `db:"ends_at"`
+	Priority         int         `db:"priority"`
+	Status           string      `db:"status"`
+	PaymentReference *string     `db:"payment_reference"`
+	ReviewedBy       *uuid.UUID  `db:"reviewed_by"`
+	ReviewedAt       *time.Time  `db:"reviewed_at"`
+	Impressions      int64       `db:"impressions"`
+	Clicks           int64       `db:"clicks"`
+	CreatedAt        time.Time   `db:"created_at"`
+	UpdatedAt        time.Time   `db:"updated_at"`
+}
+
+// IsActive reports whether the promotion should currently be shown.
+func (p *Promotion) IsActive(now time.Time) bool {
+	return p.Status == StatusApproved && !now.Before(p.StartsAt) && now.Before(p.EndsAt)
+}
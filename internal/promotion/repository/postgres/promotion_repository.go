@@ -0,0 +1,192 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	pageDomain "github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/internal/promotion/domain"
+	"github.com/cavidyrm/instawall/pkg/database"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// PromotionRepository provides a database implementation for promotions.
+type PromotionRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewPromotionRepository creates a new PromotionRepository. A nil logger means
+// slog.Default().
+func NewPromotionRepository(db *sqlx.DB, logger *slog.Logger) *PromotionRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PromotionRepository{db: db, logger: logger}
+}
+
+// CreatePromotion saves a new pending promotion and its target categories.
+func (r *PromotionRepository) CreatePromotion(ctx context.Context, p *domain.Promotion) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	query := `INSERT INTO promotions (page_id, requested_by, starts_at, ends_at, priority, payment_reference)
+			  VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, status, created_at, updated_at`
+	if err := tx.QueryRowxContext(ctx, query, p.PageID, p.RequestedBy, p.StartsAt, p.EndsAt, p.Priority, p.PaymentReference).
+		Scan(&p.ID, &p.Status, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return err
+	}
+	for _, catID := range p.CategoryIDs {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO promotion_categories (promotion_id, category_id) VALUES ($1, $2)`, p.ID, catID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetPromotionByID retrieves a single promotion with its target categories.
+func (r *PromotionRepository) GetPromotionByID(ctx context.Context, id uuid.UUID) (*domain.Promotion, error) {
+	var p domain.Promotion
+	if err := r.db.GetContext(ctx, &p, `SELECT * FROM promotions WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	promos := []domain.Promotion{p}
+	if err := r.loadCategories(ctx, promos); err != nil {
+		return nil, err
+	}
+	return &promos[0], nil
+}
+
+// GetPromotions retrieves promotions, newest first, optionally filtered by
+// requesting user and status.
+func (r *PromotionRepository) GetPromotions(ctx context.Context, requestedBy *uuid.UUID, status string, limit, offset int) ([]domain.Promotion, error) {
+	var promos []domain.Promotion
+	query := `SELECT * FROM promotions
+			  WHERE ($1::uuid IS NULL OR requested_by = $1) AND ($2 = '' OR status = $2)
+			  ORDER BY created_at DESC LIMIT $3 OFFSET $4`
+	if err := r.db.SelectContext(ctx, &promos, query, requestedBy, status, limit, offset); err != nil {
+		return nil, err
+	}
+	return promos, r.loadCategories(ctx, promos)
+}
+
+// loadCategories fills CategoryIDs for the given promotions in one query.
+func (r *PromotionRepository) loadCategories(ctx context.Context, promos []domain.Promotion) error {
+	if len(promos) == 0 {
+		return nil
+	}
+	ids := make([]string, len(promos))
+	index := make(map[uuid.UUID]int, len(promos))
+	for i, p := range promos {
+		ids[i] = p.ID.String()
+		index[p.ID] = i
+	}
+
+	var links []struct {
+		PromotionID uuid.UUID `db:"promotion_id"`
+		CategoryID  uuid.UUID `db:"category_id"`
+	}
+	query := `SELECT promotion_id, category_id FROM promotion_categories WHERE promotion_id = ANY($1::uuid[])`
+	if err := r.db.SelectContext(ctx, &links, query, pq.Array(ids)); err != nil {
+		return err
+	}
+	for _, l := range links {
+		i := index[l.PromotionID]
+		promos[i].CategoryIDs = append(promos[i].CategoryIDs, l.CategoryID)
+	}
+	return nil
+}
+
+// ReviewPromotion moves a pending promotion to approved or rejected.
+func (r *PromotionRepository) ReviewPromotion(ctx context.Context, id, reviewerID uuid.UUID, status string, priority *int) error {
+	query := `UPDATE promotions SET status = $1, priority = COALESCE($2, priority), reviewed_by = $3, reviewed_at = NOW()
+			  WHERE id = $4 AND status = 'pending'`
+	res, err := r.db.ExecContext(ctx, query, status, priority, reviewerID, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// CancelPromotion cancels a promotion that has not been rejected yet.
+func (r *PromotionRepository) CancelPromotion(ctx context.Context, id uuid.UUID) error {
+	query := `UPDATE promotions SET status = 'cancelled' WHERE id = $1 AND status IN ('pending', 'approved')`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+	return requireRow(res)
+}
+
+// GetPromotedPages picks up to n active promotions for a listing, highest
+// priority first with random rotation among equal priorities, and returns
+// their pages marked as sponsored. A nil categoryID selects promotions for
+// the unfiltered listing, which only run-of-site promotions target. A
+// non-empty tag keeps only pages that match the listing's tag filter.
+func (r *PromotionRepository) GetPromotedPages(ctx context.Context, categoryID *uuid.UUID, tag string, viewerID uuid.UUID, n int) ([]pageDomain.Page, error) {
+	var pages []pageDomain.Page
+	query := `SELECT p.*, pr.id AS promotion_id, TRUE AS sponsored,
+					 EXISTS (SELECT 1 FROM page_favorites f WHERE f.page_id = p.id AND f.user_id = $1) AS is_favorited,
+					 ARRAY(SELECT t.name FROM page_tags pt JOIN tags t ON t.id = pt.tag_id WHERE pt.page_id = p.id ORDER BY t.name) AS tags
+			  FROM promotions pr
+			  JOIN pages p ON p.id = pr.page_id
+			  WHERE pr.status = 'approved' AND pr.starts_at <= NOW() AND pr.ends_at > NOW()
+				AND p.status = 'published'
+				AND (
+					NOT EXISTS (SELECT 1 FROM promotion_categories pc WHERE pc.promotion_id = pr.id)
+					OR ($2::uuid IS NOT NULL AND EXISTS (
+						SELECT 1 FROM promotion_categories pc WHERE pc.promotion_id = pr.id AND pc.category_id = $2))
+				)
+				AND ($4 = '' OR EXISTS (
+				  SELECT 1 FROM page_tags pt JOIN tags t ON COALESCE(t.merged_into, t.id) = pt.tag_id
+				  WHERE pt.page_id = p.id AND t.name = $4))
+			  ORDER BY pr.priority DESC, random()
+			  LIMIT $3`
+	err := r.db.SelectContext(ctx, &pages, query, viewerID, categoryID, n, tag)
+	return pages, err
+}
+
+// RecordImpressions counts one impression for each of the given promotions.
+func (r *PromotionRepository) RecordImpressions(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	strIDs := make([]string, len(ids))
+	for i, id := range ids {
+		strIDs[i] = id.String()
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE promotions SET impressions = impressions + 1 WHERE id = ANY($1::uuid[])`, pq.Array(strIDs))
+	return err
+}
+
+// RecordClick counts a click on the running promotion of the given page,
+// picking the one listings would show first if several overlap. It does
+// nothing if the page is not being promoted.
+func (r *PromotionRepository) RecordClick(ctx context.Context, pageID uuid.UUID) error {
+	query := `UPDATE promotions SET clicks = clicks + 1
+			  WHERE id = (
+				  SELECT id FROM promotions
+				  WHERE page_id = $1 AND status = 'approved' AND starts_at <= NOW() AND ends_at > NOW()
+				  ORDER BY priority DESC, starts_at
+				  LIMIT 1
+			  )`
+	_, err := r.db.ExecContext(ctx, query, pageID)
+	return err
+}
+
+func requireRow(res sql.Result) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	pageDomain "github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/internal/promotion/domain"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/internal/promotion/usecase")
+
+// --- Interface Definitions for Dependencies ---
+type PromotionRepository interface {
+	CreatePromotion(ctx context.Context, p *domain.Promotion) error
+	GetPromotionByID(ctx context.Context, id uuid.UUID) (*domain.Promotion, error)
+	GetPromotions(ctx context.Context, requestedBy *uuid.UUID, status string, limit, offset int) ([]domain.Promotion, error)
+	ReviewPromotion(ctx context.Context, id, reviewerID uuid.UUID, status string, priority *int) error
+	CancelPromotion(ctx context.Context, id uuid.UUID) error
+	GetPromotedPages(ctx context.Context, categoryID *uuid.UUID, tag string, viewerID uuid.UUID, n int) ([]pageDomain.Page, error)
+	RecordImpressions(ctx context.Context, ids []uuid.UUID) error
+	RecordClick(ctx context.Context, pageID uuid.UUID) error
+}
+type PageReader interface {
+	GetPageByID(ctx context.Context, pageID uuid.UUID) (*pageDomain.Page, error)
+}
+
+// --- Usecase Implementation ---
+type PromotionUsecase struct {
+	promoRepo PromotionRepository
+	pageRepo  PageReader
+	logger    *slog.Logger
+}
+
+// NewPromotionUsecase creates a PromotionUsecase. A nil logger means
+// slog.Default().
+func NewPromotionUsecase(pr PromotionRepository, pages PageReader, logger *slog.Logger) *PromotionUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &PromotionUsecase{promoRepo: pr, pageRepo: pages, logger: logger}
+}
+
+// --- Input DTOs ---
+type CreatePromotionInput struct {
+	UserID           uuid.UUID
+	PageID           uuid.UUID
+	CategoryIDs      []uuid.UUID
+	StartsAt         time.Time
+	EndsAt           time.Time
+	PaymentReference string
+}
+
+// --- Usecase Methods ---
+
+// CreatePromotion submits a promotion of the user's own page for admin review.
+func (uc *PromotionUsecase) CreatePromotion(ctx context.Context, input CreatePromotionInput) (*domain.Promotion, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.CreatePromotion")
+	defer span.End()
+
+	if !input.EndsAt.After(input.StartsAt) {
+		return nil, apperror.Invalid("invalid_schedule", "ends_at must be after starts_at")
+	}
+	if input.EndsAt.Before(time.Now()) {
+		return nil, apperror.Invalid("invalid_schedule", "ends_at is in the past")
+	}
+	p, err := uc.pageRepo.GetPageByID(ctx, input.PageID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	if p.UserID != input.UserID {
+		return nil, apperror.Forbidden("user does not own this page")
+	}
+
+	promo := &domain.Promotion{
+		PageID:      input.PageID,
+		RequestedBy: input.UserID,
+		CategoryIDs: input.CategoryIDs,
+		StartsAt:    input.StartsAt,
+		EndsAt:      input.EndsAt,
+	}
+	if input.PaymentReference != "" {
+		promo.PaymentReference = &input.PaymentReference
+	}
+	if err := uc.promoRepo.CreatePromotion(ctx, promo); err != nil {
+		return nil, apperror.FromDB(err, "promotion")
+	}
+	return promo, nil
+}
+
+// GetMyPromotions lists the promotions a user has requested.
+func (uc *PromotionUsecase) GetMyPromotions(ctx context.Context, userID uuid.UUID, limit, offset int) ([]domain.Promotion, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.GetMyPromotions")
+	defer span.End()
+
+	return uc.promoRepo.GetPromotions(ctx, &userID, "", limit, offset)
+}
+
+// GetPromotions lists all promotions, optionally with one status, for admins.
+func (uc *PromotionUsecase) GetPromotions(ctx context.Context, status string, limit, offset int) ([]domain.Promotion, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.GetPromotions")
+	defer span.End()
+
+	return uc.promoRepo.GetPromotions(ctx, nil, status, limit, offset)
+}
+
+// CancelPromotion lets the requesting user withdraw a promotion.
+func (uc *PromotionUsecase) CancelPromotion(ctx context.Context, id, userID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.CancelPromotion")
+	defer span.End()
+
+	promo, err := uc.promoRepo.GetPromotionByID(ctx, id)
+	if err != nil {
+		return apperror.FromDB(err, "promotion")
+	}
+	if promo.RequestedBy != userID {
+		return apperror.Forbidden("user did not request this promotion")
+	}
+	if err := uc.promoRepo.CancelPromotion(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperror.Conflict("promotion_not_cancellable", "promotion can no longer be cancelled")
+		}
+		return err
+	}
+	return nil
+}
+
+// ApprovePromotion approves a pending promotion, optionally overriding its priority.
+func (uc *PromotionUsecase) ApprovePromotion(ctx context.Context, id, adminID uuid.UUID, priority *int) (*domain.Promotion, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.ApprovePromotion")
+	defer span.End()
+
+	return uc.review(ctx, id, adminID, domain.StatusApproved, priority)
+}
+
+// RejectPromotion rejects a pending promotion.
+func (uc *PromotionUsecase) RejectPromotion(ctx context.Context, id, adminID uuid.UUID) (*domain.Promotion, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.RejectPromotion")
+	defer span.End()
+
+	return uc.review(ctx, id, adminID, domain.StatusRejected, nil)
+}
+
+func (uc *PromotionUsecase) review(ctx context.Context, id, adminID uuid.UUID, status string, priority *int) (*domain.Promotion, error) {
+	if err := uc.promoRepo.ReviewPromotion(ctx, id, adminID, status, priority); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, getErr := uc.promoRepo.GetPromotionByID(ctx, id); getErr != nil {
+				return nil, apperror.FromDB(getErr, "promotion")
+			}
+			return nil, apperror.Conflict("promotion_not_pending", "promotion is not pending review")
+		}
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "promotion reviewed", "promotion_id", id, "status", status, "admin_id", adminID)
+	return uc.promoRepo.GetPromotionByID(ctx, id)
+}
+
+// PromotedPages returns up to n sponsored pages for a listing filtered by
+// category and tag.
+func (uc *PromotionUsecase) PromotedPages(ctx context.Context, categoryID *uuid.UUID, tag string, viewerID uuid.UUID, n int) ([]pageDomain.Page, error) {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.PromotedPages")
+	defer span.End()
+
+	if n <= 0 {
+		return nil, nil
+	}
+	return uc.promoRepo.GetPromotedPages(ctx, categoryID, tag, viewerID, n)
+}
+
+// RecordImpressions counts one impression for each promotion shown.
+func (uc *PromotionUsecase) RecordImpressions(ctx context.Context, ids []uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.RecordImpressions")
+	defer span.End()
+
+	return uc.promoRepo.RecordImpressions(ctx, ids)
+}
+
+// RecordClick counts a click-through from a sponsored slot on the page's
+// running promotion.
+func (uc *PromotionUsecase) RecordClick(ctx context.Context, pageID uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "PromotionUsecase.RecordClick")
+	defer span.End()
+
+	return uc.promoRepo.RecordClick(ctx, pageID)
+}
@@ -0,0 +1,113 @@
+// Package ratelimit implements token buckets for API rate limiting. A bucket
+// holds up to Limit tokens and refills at Limit per Window; each request
+// takes one token and is rejected when none is left. Buckets are kept in
+// Redis, so that every instance of the service enforces the same limit, or
+// in memory for tests and single-instance deployments.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Rule allows Limit requests per Window, in bursts of up to Limit.
+type Rule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Result describes a bucket after a request took, or failed to take, a token.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next token, for rejected requests.
+	RetryAfter time.Duration
+}
+
+// Store takes tokens from the buckets identified by key.
+type Store interface {
+	Take(ctx context.Context, key string, rule Rule) (Result, error)
+}
+
+// result builds the Result of a bucket left with tokens.
+func result(rule Rule, tokens float64, allowed bool) Result {
+	perToken := float64(rule.Window) / float64(rule.Limit)
+	res := Result{
+		Allowed:   allowed,
+		Limit:     rule.Limit,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(rule.Limit) - tokens) * perToken),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) * perToken)
+	}
+	return res
+}
+
+// refill returns the tokens of a bucket that held tokens elapsed ago.
+func refill(rule Rule, tokens float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return tokens
+	}
+	added := float64(elapsed) / float64(rule.Window) * float64(rule.Limit)
+	return math.Min(float64(rule.Limit), tokens+added)
+}
+
+// MemoryStore keeps buckets in memory. Limits are not shared between
+// instances of the service.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens float64
+	at     time.Time
+	full   time.Time // when the bucket is full again and can be forgotten
+}
+
+// sweepInterval is how often MemoryStore forgets full buckets.
+const sweepInterval = time.Minute
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+// Take takes a token from the bucket for key.
+func (s *MemoryStore) Take(_ context.Context, key string, rule Rule) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.lastSweep) >= sweepInterval {
+		for k, b := range s.buckets {
+			if !now.Before(b.full) {
+				delete(s.buckets, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(rule.Limit), at: now}
+		s.buckets[key] = b
+	}
+	b.tokens = refill(rule, b.tokens, now.Sub(b.at))
+	b.at = now
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	res := result(rule, b.tokens, allowed)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock is a fake time source for MemoryStore.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestStore() (*MemoryStore, *clock) {
+	c := &clock{t: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	s := NewMemoryStore()
+	s.now = c.now
+	return s, c
+}
+
+func take(t *testing.T, s *MemoryStore, key string, rule Rule) Result {
+	t.Helper()
+	res, err := s.Take(context.Background(), key, rule)
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	return res
+}
+
+func TestMemoryStoreBurstThenReject(t *testing.T) {
+	s, _ := newTestStore()
+	rule := Rule{Limit: 3, Window: time.Minute}
+
+	for i := range 3 {
+		res := take(t, s, "k", rule)
+		if !res.Allowed {
+			t.Fatalf("request %d rejected", i+1)
+		}
+		if want := 2 - i; res.Remaining != want {
+			t.Errorf("request %d: Remaining = %d, want %d", i+1, res.Remaining, want)
+		}
+		if want := time.Duration(i+1) * 20 * time.Second; res.Reset != want {
+			t.Errorf("request %d: Reset = %s, want %s", i+1, res.Reset, want)
+		}
+		if res.RetryAfter != 0 {
+			t.Errorf("request %d: RetryAfter = %s, want 0", i+1, res.RetryAfter)
+		}
+	}
+
+	res := take(t, s, "k", rule)
+	if res.Allowed {
+		t.Fatal("request over the limit allowed")
+	}
+	if res.Remaining != 0 || res.Limit != 3 {
+		t.Errorf("Remaining, Limit = %d, %d, want 0, 3", res.Remaining, res.Limit)
+	}
+	if res.RetryAfter != 20*time.Second {
+		t.Errorf("RetryAfter = %s, want 20s", res.RetryAfter)
+	}
+	if res.Reset != time.Minute {
+		t.Errorf("Reset = %s, want 1m", res.Reset)
+	}
+}
+
+func TestMemoryStoreRefill(t *testing.T) {
+	s, c := newTestStore()
+	rule := Rule{Limit: 2, Window: time.Minute}
+
+	take(t, s, "k", rule)
+	take(t, s, "k", rule)
+	if take(t, s, "k", rule).Allowed {
+		t.Fatal("empty bucket allowed a request")
+	}
+
+	// One token comes back every 30s; a rejected request takes nothing.
+	c.advance(15 * time.Second)
+	res := take(t, s, "k", rule)
+	if res.Allowed {
+		t.Fatal("request allowed before a token was refilled")
+	}
+	if res.RetryAfter != 15*time.Second {
+		t.Errorf("RetryAfter = %s, want 15s", res.RetryAfter)
+	}
+	c.advance(15 * time.Second)
+	if !take(t, s, "k", rule).Allowed {
+		t.Fatal("request rejected after a token was refilled")
+	}
+
+	// A bucket never holds more than Limit tokens.
+	c.advance(time.Hour)
+	if res := take(t, s, "k", rule); !res.Allowed || res.Remaining != 1 {
+		t.Errorf("after an idle hour: Allowed, Remaining = %t, %d, want true, 1", res.Allowed, res.Remaining)
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	s, _ := newTestStore()
+	rule := Rule{Limit: 1, Window: time.Minute}
+
+	if !take(t, s, "a", rule).Allowed {
+		t.Fatal("first request for a rejected")
+	}
+	if take(t, s, "a", rule).Allowed {
+		t.Fatal("second request for a allowed")
+	}
+	if !take(t, s, "b", rule).Allowed {
+		t.Fatal("first request for b rejected")
+	}
+}
+
+func TestMemoryStoreForgetsFullBuckets(t *testing.T) {
+	s, c := newTestStore()
+	rule := Rule{Limit: 2, Window: time.Minute}
+
+	take(t, s, "idle", rule)
+	c.advance(sweepInterval)
+	take(t, s, "busy", rule)
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("full bucket kept after a sweep")
+	}
+	if _, ok := s.buckets["busy"]; !ok {
+		t.Error("bucket in use forgotten")
+	}
+}
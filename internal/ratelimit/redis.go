@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "ratelimit:"
+
+// takeScript refills and takes from a bucket stored as a hash of its tokens
+// and the time they were counted, in milliseconds by the Redis clock so that
+// the instances' clocks don't matter. The bucket expires once it would be
+// full again. Tokens are returned as a string because Redis truncates Lua
+// numbers to integers.
+var takeScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(state[1]) or limit
+local at = tonumber(state[2]) or now
+if now > at then
+	tokens = math.min(limit, tokens + (now - at) * limit / window)
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((limit - tokens) * window / limit) + 1)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps buckets in Redis, shared by every instance of the
+// service.
+type RedisStore struct {
+	rdb *redis.Client
+}
+
+// NewRedisStore creates a new RedisStore.
+func NewRedisStore(rdb *redis.Client) *RedisStore {
+	return &RedisStore{rdb: rdb}
+}
+
+// Take takes a token from the bucket for key.
+func (s *RedisStore) Take(ctx context.Context, key string, rule Rule) (Result, error) {
+	reply, err := takeScript.Run(ctx, s.rdb, []string{keyPrefix + key}, rule.Limit, rule.Window.Milliseconds()).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	allowed, _ := reply[0].(int64)
+	tokensStr, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(rule, tokens, allowed == 1), nil
+}
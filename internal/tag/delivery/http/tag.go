@@ -0,0 +1,155 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
+	"github.com/cavidyrm/instawall/internal/tag/usecase"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type TagHandler struct {
+	tagUsecase *usecase.TagUsecase
+}
+
+func RegisterTagHandlers(e *echo.Echo, uc *usecase.TagUsecase) {
+	h := &TagHandler{tagUsecase: uc}
+
+	// Public autocomplete and page tags
+	e.GET("/tags", h.SearchTags)
+	e.GET("/pages/:id/tags", h.GetPageTags)
+
+	// Page owners set the tags of their pages
+	e.PUT("/pages/:id/tags", h.SetPageTags, appMiddleware.JWTAuthMiddleware)
+
+	// Admin-only tag moderation
+	adminGroup := e.Group("/admin/tags")
+	adminGroup.Use(appMiddleware.JWTAuthMiddleware, appMiddleware.AdminOnlyMiddleware)
+	adminGroup.GET("", h.GetTags)
+	adminGroup.POST("/:id/merge", h.MergeTags)
+	adminGroup.POST("/:id/ban", h.BanTag)
+	adminGroup.DELETE("/:id/ban", h.UnbanTag)
+}
+
+// Request Structs
+type SetPageTagsRequest struct {
+	Tags []string `json:"tags" validate:"required,dive,max=100"`
+}
+type MergeTagsRequest struct {
+	Into string `json:"into" validate:"required,uuid"`
+}
+
+// --- Handler Methods ---
+
+func (h *TagHandler) SearchTags(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	if limit <= 0 || limit > 50 {
+		limit = 10
+	}
+	tags, err := h.tagUsecase.SearchTags(c.Request().Context(), c.QueryParam("prefix"), limit)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) GetPageTags(c echo.Context) error {
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+	tags, err := h.tagUsecase.GetPageTags(c.Request().Context(), pageID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) SetPageTags(c echo.Context) error {
+	userID, err := uuid.Parse(c.Get("user_id").(string))
+	if err != nil {
+		return apperror.Unauthorized("invalid user ID in token")
+	}
+	pageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid page ID")
+	}
+	var req SetPageTagsRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+
+	tags, err := h.tagUsecase.SetPageTags(c.Request().Context(), pageID, userID, req.Tags)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) GetTags(c echo.Context) error {
+	limit, _ := strconv.Atoi(c.QueryParam("limit"))
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	tags, err := h.tagUsecase.GetTags(c.Request().Context(), c.QueryParam("banned") == "true", limit, offset)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, tags)
+}
+
+func (h *TagHandler) MergeTags(c echo.Context) error {
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid tag ID")
+	}
+	var req MergeTagsRequest
+	if err := c.Bind(&req); err != nil {
+		return apperror.Validation("invalid request")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
+	}
+	targetID, err := uuid.Parse(req.Into)
+	if err != nil {
+		return apperror.Validation("invalid target tag ID")
+	}
+
+	target, err := h.tagUsecase.MergeTags(c.Request().Context(), sourceID, targetID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, target)
+}
+
+func (h *TagHandler) BanTag(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid tag ID")
+	}
+	if err := h.tagUsecase.BanTag(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *TagHandler) UnbanTag(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return apperror.Validation("invalid tag ID")
+	}
+	if err := h.tagUsecase.UnbanTag(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
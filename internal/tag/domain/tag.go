@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum length of a tag name in characters.
+const MaxLength = 50
+
+// Tag is a free-form label users attach to their pages.
+type Tag struct {
+	ID         uuid.UUID  `db:"id"`
+	Name       string     `db:"name"`
+	Banned     bool       `db:"banned"`
+	MergedInto *uuid.UUID `db:"merged_into"`
+	PageCount  int        `db:"page_count"`
+	CreatedAt  time.Time  `db:"created_at"`
+}
+
+// ErrInvalidTag is returned for tags that are empty, too long or contain
+// characters other than letters, digits and separators.
+var ErrInvalidTag = errors.New("invalid tag")
+
+// BannedTagError is returned when a page is given a banned tag.
+type BannedTagError struct {
+	Name string
+}
+
+func (e *BannedTagError) Error() string {
+	return fmt.Sprintf("tag %q is not allowed", e.Name)
+}
+
+// Normalize returns the canonical form of a tag: a leading '#' is dropped,
+// compatibility characters are unified, case is folded, Arabic letter forms
+// are mapped to their Persian equivalents and runs of spaces, hyphens and
+// underscores become a single hyphen. "#Hand Made" and "hand-made" both
+// become "hand-made".
+func Normalize(raw string) (string, error) {
+	s := strings.TrimLeft(strings.TrimSpace(raw), "#")
+	s = norm.NFC.String(cases.Fold().String(norm.NFKC.String(s)))
+	s = strings.NewReplacer("ي", "ی", "ك", "ک", "ى", "ی").Replace(s)
+
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_'
+	})
+	s = strings.Join(parts, "-")
+	if s == "" || utf8.RuneCountInString(s) > MaxLength {
+		return "", fmt.Errorf("%w %q", ErrInvalidTag, raw)
+	}
+	for _, r := range s {
+		// U+200C, the zero-width non-joiner, is part of Persian spelling.
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r) && r != '-' && r != '\u200c' {
+			return "", fmt.Errorf("%w %q", ErrInvalidTag, raw)
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"#Hand Made", "hand-made"},
+		{"hand-made", "hand-made"},
+		{"  hand__made--  ", "hand-made"},
+		{"##golang", "golang"},
+		{"ＧＯ", "go"},
+		{"STRASSE", "strasse"},
+		{"كيف", "کیف"},
+		{"هدیه ي", "هدیه-ی"},
+		{"می‌خواهم", "می‌خواهم"},
+		{"کتاب۱۴۰۳", "کتاب۱۴۰۳"},
+		{strings.Repeat("a", MaxLength), strings.Repeat("a", MaxLength)},
+		{strings.Repeat("ب", MaxLength), strings.Repeat("ب", MaxLength)},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+
+	for _, raw := range []string{
+		"", "#", " - _ ", "c++", "rock&roll", "hello!", "tag.name", "😀",
+		strings.Repeat("a", MaxLength+1),
+		strings.Repeat("ب", MaxLength+1),
+	} {
+		if got, err := Normalize(raw); !errors.Is(err, ErrInvalidTag) {
+			t.Errorf("Normalize(%q) = %q, %v; want ErrInvalidTag", raw, got, err)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"strings"
+
+	"github.com/cavidyrm/instawall/internal/tag/domain"
+	"github.com/cavidyrm/instawall/pkg/database"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// TagRepository provides a database implementation for tags.
+type TagRepository struct {
+	db     *sqlx.DB
+	logger *slog.Logger
+}
+
+// NewTagRepository creates a new TagRepository. A nil logger means
+// slog.Default().
+func NewTagRepository(db *sqlx.DB, logger *slog.Logger) *TagRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TagRepository{db: db, logger: logger}
+}
+
+// GetTagByID retrieves a single tag.
+func (r *TagRepository) GetTagByID(ctx context.Context, id uuid.UUID) (*domain.Tag, error) {
+	var t domain.Tag
+	err := r.db.GetContext(ctx, &t, `SELECT * FROM tags WHERE id = $1`, id)
+	return &t, err
+}
+
+// SetPageTags replaces the tags of a page with the given normalized names,
+// creating tags that do not exist yet. Names of merged tags resolve to the
+// tag they were merged into. It returns a *domain.BannedTagError, changing
+// nothing, if any of the tags is banned.
+func (r *TagRepository) SetPageTags(ctx context.Context, pageID uuid.UUID, names []string) ([]domain.Tag, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	if _, err := tx.ExecContext(ctx, `INSERT INTO tags (name) SELECT unnest($1::text[]) ON CONFLICT (name) DO NOTHING`, pq.Array(names)); err != nil {
+		return nil, err
+	}
+	var tags []domain.Tag
+	query := `SELECT DISTINCT t.* FROM tags n JOIN tags t ON t.id = COALESCE(n.merged_into, n.id)
+			  WHERE n.name = ANY($1::text[]) ORDER BY t.name`
+	if err := tx.SelectContext(ctx, &tags, query, pq.Array(names)); err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(tags))
+	for i, t := range tags {
+		if t.Banned {
+			return nil, &domain.BannedTagError{Name: t.Name}
+		}
+		ids[i] = t.ID.String()
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM page_tags WHERE page_id = $1 AND tag_id <> ALL($2::uuid[])`, pageID, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	query = `INSERT INTO page_tags (page_id, tag_id) SELECT $1, unnest($2::uuid[]) ON CONFLICT DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, pageID, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	// Reload to pick up the page counts updated by the insert.
+	tags = nil
+	if err := tx.SelectContext(ctx, &tags, `SELECT * FROM tags WHERE id = ANY($1::uuid[]) ORDER BY name`, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	return tags, tx.Commit()
+}
+
+// GetPageTags retrieves the tags of a page in alphabetical order.
+func (r *TagRepository) GetPageTags(ctx context.Context, pageID uuid.UUID) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	query := `SELECT t.* FROM tags t JOIN page_tags pt ON pt.tag_id = t.id WHERE pt.page_id = $1 ORDER BY t.name`
+	err := r.db.SelectContext(ctx, &tags, query, pageID)
+	return tags, err
+}
+
+// SearchTags retrieves usable tags starting with a normalized prefix, most
+// used first.
+func (r *TagRepository) SearchTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	query := `SELECT * FROM tags
+			  WHERE name LIKE $1 ESCAPE '\' AND NOT banned AND merged_into IS NULL
+			  ORDER BY page_count DESC, name ASC LIMIT $2`
+	err := r.db.SelectContext(ctx, &tags, query, escapeLike(prefix)+"%", limit)
+	return tags, err
+}
+
+// GetTags retrieves tags for administration, optionally only banned ones,
+// alphabetically.
+func (r *TagRepository) GetTags(ctx context.Context, bannedOnly bool, limit, offset int) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	query := `SELECT * FROM tags WHERE NOT $1 OR banned ORDER BY name ASC LIMIT $2 OFFSET $3`
+	err := r.db.SelectContext(ctx, &tags, query, bannedOnly, limit, offset)
+	return tags, err
+}
+
+// MergeTags moves every page from the source tag to the target tag and
+// makes the source an alias of the target, in a single transaction.
+func (r *TagRepository) MergeTags(ctx context.Context, sourceID, targetID uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	query := `INSERT INTO page_tags (page_id, tag_id)
+			  SELECT page_id, $1 FROM page_tags WHERE tag_id = $2
+			  ON CONFLICT DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, targetID, sourceID); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM page_tags WHERE tag_id = $1`, sourceID); err != nil {
+		return err
+	}
+	// Earlier aliases of the source follow it to the target.
+	query = `UPDATE tags SET merged_into = $1 WHERE id = $2 OR merged_into = $2`
+	if _, err := tx.ExecContext(ctx, query, targetID, sourceID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetBanned bans or unbans a tag. Banning removes it from every page.
+func (r *TagRepository) SetBanned(ctx context.Context, id uuid.UUID, banned bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer database.Rollback(ctx, r.logger, tx)
+
+	res, err := tx.ExecContext(ctx, `UPDATE tags SET banned = $1 WHERE id = $2`, banned, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return sql.ErrNoRows
+	}
+	if banned {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM page_tags WHERE tag_id = $1`, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
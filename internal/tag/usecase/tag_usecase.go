@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	pageDomain "github.com/cavidyrm/instawall/internal/page/domain"
+	"github.com/cavidyrm/instawall/internal/tag/domain"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+)
+
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/internal/tag/usecase")
+
+// --- Interface Definitions for Dependencies ---
+type TagRepository interface {
+	GetTagByID(ctx context.Context, id uuid.UUID) (*domain.Tag, error)
+	SetPageTags(ctx context.Context, pageID uuid.UUID, names []string) ([]domain.Tag, error)
+	GetPageTags(ctx context.Context, pageID uuid.UUID) ([]domain.Tag, error)
+	SearchTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error)
+	GetTags(ctx context.Context, bannedOnly bool, limit, offset int) ([]domain.Tag, error)
+	MergeTags(ctx context.Context, sourceID, targetID uuid.UUID) error
+	SetBanned(ctx context.Context, id uuid.UUID, banned bool) error
+}
+type PageReader interface {
+	GetPageByID(ctx context.Context, pageID uuid.UUID) (*pageDomain.Page, error)
+}
+
+// --- Usecase Implementation ---
+type TagUsecase struct {
+	tagRepo    TagRepository
+	pageRepo   PageReader
+	maxPerPage int
+	logger     *slog.Logger
+}
+
+// NewTagUsecase creates a TagUsecase that allows up to maxPerPage tags on
+// each page. A nil logger means slog.Default().
+func NewTagUsecase(tr TagRepository, pages PageReader, maxPerPage int, logger *slog.Logger) *TagUsecase {
+	if maxPerPage <= 0 {
+		maxPerPage = 10
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &TagUsecase{tagRepo: tr, pageRepo: pages, maxPerPage: maxPerPage, logger: logger}
+}
+
+// --- Usecase Methods ---
+
+// SetPageTags replaces the tags of a page owned by the user. Tags are
+// normalized and duplicates after normalization are dropped.
+func (uc *TagUsecase) SetPageTags(ctx context.Context, pageID, userID uuid.UUID, raw []string) ([]domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.SetPageTags")
+	defer span.End()
+
+	p, err := uc.pageRepo.GetPageByID(ctx, pageID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "page")
+	}
+	if p.UserID != userID {
+		return nil, apperror.Forbidden("user does not own this page")
+	}
+
+	names := make([]string, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, r := range raw {
+		if strings.TrimSpace(r) == "" {
+			continue
+		}
+		name, err := domain.Normalize(r)
+		if err != nil {
+			return nil, invalidTag(err)
+		}
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	if len(names) > uc.maxPerPage {
+		return nil, apperror.Invalid("too_many_tags", fmt.Sprintf("a page can have at most %d tags", uc.maxPerPage))
+	}
+	tags, err := uc.tagRepo.SetPageTags(ctx, pageID, names)
+	var banned *domain.BannedTagError
+	if errors.As(err, &banned) {
+		return nil, apperror.Invalid("banned_tag", err.Error()).With("tag", banned.Name).Wrap(err)
+	}
+	return tags, err
+}
+
+func (uc *TagUsecase) GetPageTags(ctx context.Context, pageID uuid.UUID) ([]domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.GetPageTags")
+	defer span.End()
+
+	return uc.tagRepo.GetPageTags(ctx, pageID)
+}
+
+// SearchTags suggests tags starting with prefix for autocompletion.
+func (uc *TagUsecase) SearchTags(ctx context.Context, prefix string, limit int) ([]domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.SearchTags")
+	defer span.End()
+
+	if strings.TrimSpace(prefix) == "" {
+		return uc.tagRepo.SearchTags(ctx, "", limit)
+	}
+	normalized, err := domain.Normalize(prefix)
+	if err != nil {
+		return nil, invalidTag(err)
+	}
+	return uc.tagRepo.SearchTags(ctx, normalized, limit)
+}
+
+// GetTags lists tags for administration.
+func (uc *TagUsecase) GetTags(ctx context.Context, bannedOnly bool, limit, offset int) ([]domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.GetTags")
+	defer span.End()
+
+	return uc.tagRepo.GetTags(ctx, bannedOnly, limit, offset)
+}
+
+// MergeTags folds the source tag into the target: pages tagged with the
+// source are tagged with the target instead, and future uses of the source
+// name resolve to the target.
+func (uc *TagUsecase) MergeTags(ctx context.Context, sourceID, targetID uuid.UUID) (*domain.Tag, error) {
+	ctx, span := tracer.Start(ctx, "TagUsecase.MergeTags")
+	defer span.End()
+
+	source, err := uc.tagRepo.GetTagByID(ctx, sourceID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "tag")
+	}
+	target, err := uc.tagRepo.GetTagByID(ctx, targetID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "target tag")
+	}
+	if source.MergedInto != nil {
+		return nil, apperror.Invalid("invalid_merge", "tag has already been merged")
+	}
+	if target.MergedInto != nil {
+		return nil, apperror.Invalid("invalid_merge", "target tag has itself been merged")
+	}
+	if source.ID == target.ID {
+		return nil, apperror.Invalid("invalid_merge", "a tag cannot be merged into itself")
+	}
+	if err := uc.tagRepo.MergeTags(ctx, source.ID, target.ID); err != nil {
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "tags merged", "source_id", source.ID, "target_id", target.ID)
+	return uc.tagRepo.GetTagByID(ctx, target.ID)
+}
+
+// BanTag bans a tag and removes it from every page.
+func (uc *TagUsecase) BanTag(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "TagUsecase.BanTag")
+	defer span.End()
+
+	return uc.setBanned(ctx, id, true)
+}
+
+// UnbanTag allows a banned tag again. Pages it was removed from do not get
+// it back.
+func (uc *TagUsecase) UnbanTag(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracer.Start(ctx, "TagUsecase.UnbanTag")
+	defer span.End()
+
+	return uc.setBanned(ctx, id, false)
+}
+
+func (uc *TagUsecase) setBanned(ctx context.Context, id uuid.UUID, banned bool) error {
+	if err := uc.tagRepo.SetBanned(ctx, id, banned); err != nil {
+		return apperror.FromDB(err, "tag")
+	}
+	uc.logger.InfoContext(ctx, "tag ban changed", "tag_id", id, "banned", banned)
+	return nil
+}
+
+func invalidTag(err error) error {
+	return apperror.Invalid("invalid_tag", err.Error()).Wrap(err)
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/tag/domain"
+	"github.com/google/uuid"
+)
+
+// fakeTagRepo holds tags by ID. Methods the tests don't exercise panic
+// through the nil embedded interface.
+type fakeTagRepo struct {
+	TagRepository
+	tags   map[uuid.UUID]domain.Tag
+	merged bool
+}
+
+func (r *fakeTagRepo) GetTagByID(_ context.Context, id uuid.UUID) (*domain.Tag, error) {
+	t, ok := r.tags[id]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+	return &t, nil
+}
+
+func (r *fakeTagRepo) MergeTags(_ context.Context, sourceID, targetID uuid.UUID) error {
+	r.merged = true
+	return nil
+}
+
+func TestMergeTags(t *testing.T) {
+	live, other, merged := uuid.New(), uuid.New(), uuid.New()
+	tags := map[uuid.UUID]domain.Tag{
+		live:   {ID: live, Name: "handmade"},
+		other:  {ID: other, Name: "hand-made"},
+		merged: {ID: merged, Name: "handcraft", MergedInto: &live},
+	}
+
+	tests := []struct {
+		name           string
+		source, target uuid.UUID
+		wantKind       apperror.Kind
+		wantCode       string
+	}{
+		{name: "merges", source: other, target: live},
+		{name: "source already merged", source: merged, target: other, wantKind: apperror.KindValidation, wantCode: "invalid_merge"},
+		{name: "target already merged", source: other, target: merged, wantKind: apperror.KindValidation, wantCode: "invalid_merge"},
+		{name: "into itself", source: live, target: live, wantKind: apperror.KindValidation, wantCode: "invalid_merge"},
+		{name: "missing source", source: uuid.New(), target: live, wantKind: apperror.KindNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeTagRepo{tags: tags}
+			uc := NewTagUsecase(repo, nil, 0, nil)
+
+			_, err := uc.MergeTags(context.Background(), tt.source, tt.target)
+			if tt.wantKind == 0 {
+				if err != nil || !repo.merged {
+					t.Fatalf("MergeTags: err = %v, merged = %t; want a merge", err, repo.merged)
+				}
+				return
+			}
+			appErr, ok := apperror.As(err)
+			if !ok || appErr.Kind != tt.wantKind || (tt.wantCode != "" && appErr.Code != tt.wantCode) {
+				t.Errorf("MergeTags: err = %v, want kind %d %s", err, tt.wantKind, tt.wantCode)
+			}
+			if repo.merged {
+				t.Error("tags merged despite the error")
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package http
 import (
 	"net/http"
 
+	"github.com/cavidyrm/instawall/internal/apperror"
 	appMiddleware "github.com/cavidyrm/instawall/internal/middleware"
 	"github.com/cavidyrm/instawall/internal/user/usecase"
 	"github.com/labstack/echo/v4"
@@ -44,19 +45,19 @@ type handler struct {
 
 // Request/Response Structs
 type SendOTPRequest struct {
-	MobileNumber string `json:"mobile_number" validate:"required"`
+	MobileNumber string `json:"mobile_number" validate:"required,mobile"`
 }
 type VerifyOTPRequest struct {
-	MobileNumber string `json:"mobile_number" validate:"required"`
-	OTP          string `json:"otp" validate:"required"`
+	MobileNumber string `json:"mobile_number" validate:"required,mobile"`
+	OTP          string `json:"otp" validate:"required,numeric,len=6"`
 }
 type CompleteRegistrationRequest struct {
-	Name     string `json:"name" validate:"required"`
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	Name     string `json:"name" validate:"required,max=100"`
+	Email    string `json:"email" validate:"required,email,max=255"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
 }
 type LoginRequest struct {
-	MobileNumber string `json:"mobile_number" validate:"required"`
+	MobileNumber string `json:"mobile_number" validate:"required,mobile"`
 	Password     string `json:"password" validate:"required"`
 }
 type ProfileResponse struct {
@@ -71,11 +72,14 @@ type ProfileResponse struct {
 func (h *handler) SendOTP(c echo.Context) error {
 	var req SendOTPRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid request")
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 	err := h.userUsecase.SendOTP(c.Request().Context(), req.MobileNumber)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "Failed to send OTP")
+		return err
 	}
 	return c.JSON(http.StatusOK, echo.Map{"message": "OTP sent successfully"})
 }
@@ -83,11 +87,14 @@ func (h *handler) SendOTP(c echo.Context) error {
 func (h *handler) VerifyOTP(c echo.Context) error {
 	var req VerifyOTPRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid request")
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 	token, err := h.userUsecase.VerifyOTP(c.Request().Context(), req.MobileNumber, req.OTP)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": err.Error()})
+		return err
 	}
 	return c.JSON(http.StatusOK, echo.Map{"registration_token": token})
 }
@@ -96,11 +103,14 @@ func (h *handler) CompleteRegistration(c echo.Context) error {
 	mobileNumber := c.Get("verified_mobile").(string)
 	var req CompleteRegistrationRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid request")
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 	_, err := h.userUsecase.CompleteRegistration(c.Request().Context(), mobileNumber, req.Password, req.Name, req.Email)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, echo.Map{"error": "Registration failed"})
+		return err
 	}
 	return c.JSON(http.StatusCreated, echo.Map{"message": "User registered successfully"})
 }
@@ -108,11 +118,14 @@ func (h *handler) CompleteRegistration(c echo.Context) error {
 func (h *handler) Login(c echo.Context) error {
 	var req LoginRequest
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, "Invalid request")
+		return apperror.Validation("invalid request body")
+	}
+	if err := c.Validate(&req); err != nil {
+		return err
 	}
 	token, err := h.userUsecase.Login(c.Request().Context(), req.MobileNumber, req.Password)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, echo.Map{"error": "Invalid credentials"})
+		return err
 	}
 	return c.JSON(http.StatusOK, echo.Map{"token": token})
 }
@@ -121,7 +134,7 @@ func (h *handler) GetProfile(c echo.Context) error {
 	userID := c.Get("user_id").(string)
 	userProfile, err := h.userUsecase.GetProfile(c.Request().Context(), userID)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, echo.Map{"error": "User not found"})
+		return err
 	}
 	resp := &ProfileResponse{
 		ID:    userProfile.ID.String(),
@@ -2,18 +2,25 @@ package postgres
 
 import (
 	"context"
+	"log/slog"
+
 	"github.com/cavidyrm/instawall/internal/user/domain" // <-- IMPORTANT: Replace with your actual module name
 	"github.com/jmoiron/sqlx"
 )
 
 // UserRepository is a PostgreSQL implementation of the UserRepository.
 type UserRepository struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	logger *slog.Logger
 }
 
-// NewUserRepository creates a new UserRepository.
-func NewUserRepository(db *sqlx.DB) *UserRepository {
-	return &UserRepository{db: db}
+// NewUserRepository creates a new UserRepository. A nil logger means
+// slog.Default().
+func NewUserRepository(db *sqlx.DB, logger *slog.Logger) *UserRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UserRepository{db: db, logger: logger}
 }
 
 // Create creates a new user in the database.
@@ -2,18 +2,25 @@ package redis
 
 import (
 	"context"
-	"github.com/redis/go-redis/v9"
+	"log/slog"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // OTPRepository handles OTP storage and retrieval in Redis.
 type OTPRepository struct {
-	rdb *redis.Client
+	rdb    *redis.Client
+	logger *slog.Logger
 }
 
-// NewOTPRepository creates a new OTPRepository.
-func NewOTPRepository(rdb *redis.Client) *OTPRepository {
-	return &OTPRepository{rdb: rdb}
+// NewOTPRepository creates a new OTPRepository. A nil logger means
+// slog.Default().
+func NewOTPRepository(rdb *redis.Client, logger *slog.Logger) *OTPRepository {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &OTPRepository{rdb: rdb, logger: logger}
 }
 
 // StoreOTP saves the OTP for a given mobile number with a 3-minute expiration.
@@ -3,14 +3,21 @@ package usecase
 import (
 	"context"
 	"crypto/rand"
-	"fmt"
+	"database/sql"
+	"errors"
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/internal/metrics"
 	"github.com/cavidyrm/instawall/internal/middleware"
 	"github.com/cavidyrm/instawall/internal/user/domain"
+	"github.com/cavidyrm/instawall/pkg/mobile"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/crypto/bcrypt"
 	"io"
-	"log"
+	"log/slog"
 )
 
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/internal/user/usecase")
+
 // UserRepository defines the interface for user data storage.
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
@@ -28,34 +35,67 @@ type OTPRepository interface {
 type UserUsecase struct {
 	userRepo UserRepository
 	otpRepo  OTPRepository
+	logger   *slog.Logger
 }
 
-// NewUserUsecase creates a new UserUsecase.
-func NewUserUsecase(userRepo UserRepository, otpRepo OTPRepository) *UserUsecase {
-	return &UserUsecase{userRepo: userRepo, otpRepo: otpRepo}
+// NewUserUsecase creates a new UserUsecase. A nil logger means slog.Default().
+func NewUserUsecase(userRepo UserRepository, otpRepo OTPRepository, logger *slog.Logger) *UserUsecase {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &UserUsecase{userRepo: userRepo, otpRepo: otpRepo, logger: logger}
 }
 
 // SendOTP generates, stores, and "sends" an OTP.
 func (uc *UserUsecase) SendOTP(ctx context.Context, mobileNumber string) error {
+	ctx, span := tracer.Start(ctx, "UserUsecase.SendOTP")
+	defer span.End()
+
+	mobileNumber, err := normalizeMobile(mobileNumber)
+	if err != nil {
+		return err
+	}
 	otp := generateOTP(6)
-	log.Printf("Generated OTP for %s: %s\n", mobileNumber, otp) // For development
-	return uc.otpRepo.StoreOTP(ctx, mobileNumber, otp)
+	if err := uc.otpRepo.StoreOTP(ctx, mobileNumber, otp); err != nil {
+		return err
+	}
+	// The number is masked and the code redacted by the logger.
+	uc.logger.InfoContext(ctx, "OTP sent", "mobile", mobileNumber, "otp", otp)
+	metrics.OTPsSent.Inc()
+	return nil
 }
 
 // VerifyOTP checks the OTP and returns a temporary registration token if valid.
 func (uc *UserUsecase) VerifyOTP(ctx context.Context, mobileNumber, otp string) (string, error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.VerifyOTP")
+	defer span.End()
+
+	mobileNumber, err := normalizeMobile(mobileNumber)
+	if err != nil {
+		return "", err
+	}
 	storedOTP, err := uc.otpRepo.GetOTP(ctx, mobileNumber)
 	if err != nil {
-		return "", fmt.Errorf("OTP expired or not found")
+		metrics.OTPVerifications.WithLabelValues("failed").Inc()
+		return "", apperror.Unauthorized("OTP expired or not found")
 	}
 	if storedOTP != otp {
-		return "", fmt.Errorf("invalid OTP")
+		metrics.OTPVerifications.WithLabelValues("failed").Inc()
+		return "", apperror.Unauthorized("invalid OTP")
 	}
+	metrics.OTPVerifications.WithLabelValues("verified").Inc()
 	return middleware.GenerateRegistrationToken(mobileNumber)
 }
 
 // CompleteRegistration creates the user after OTP has been verified.
 func (uc *UserUsecase) CompleteRegistration(ctx context.Context, mobileNumber, password, name, email string) (*domain.User, error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.CompleteRegistration")
+	defer span.End()
+
+	mobileNumber, err := normalizeMobile(mobileNumber)
+	if err != nil {
+		return nil, err
+	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return nil, err
@@ -67,26 +107,46 @@ func (uc *UserUsecase) CompleteRegistration(ctx context.Context, mobileNumber, p
 		Email:        email,
 	}
 	if err := uc.userRepo.Create(ctx, newUser); err != nil {
-		return nil, err
+		return nil, apperror.FromDB(err, "user")
 	}
 	return newUser, nil
 }
 
 // Login authenticates a user and returns a standard JWT.
 func (uc *UserUsecase) Login(ctx context.Context, mobileNumber, password string) (string, error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.Login")
+	defer span.End()
+
+	mobileNumber, err := normalizeMobile(mobileNumber)
+	if err != nil {
+		return "", err
+	}
 	existingUser, err := uc.userRepo.GetByMobileNumber(ctx, mobileNumber)
+	if errors.Is(err, sql.ErrNoRows) {
+		metrics.Logins.WithLabelValues("failure").Inc()
+		return "", apperror.Unauthorized("invalid credentials")
+	}
 	if err != nil {
 		return "", err
 	}
 	if err := bcrypt.CompareHashAndPassword([]byte(existingUser.PasswordHash), []byte(password)); err != nil {
-		return "", err
+		metrics.Logins.WithLabelValues("failure").Inc()
+		return "", apperror.Unauthorized("invalid credentials")
 	}
+	metrics.Logins.WithLabelValues("success").Inc()
 	return middleware.GenerateToken(existingUser.ID.String(), existingUser.Name, existingUser.Role)
 }
 
 // GetProfile retrieves a user's public profile.
 func (uc *UserUsecase) GetProfile(ctx context.Context, userID string) (*domain.User, error) {
-	return uc.userRepo.GetByID(ctx, userID)
+	ctx, span := tracer.Start(ctx, "UserUsecase.GetProfile")
+	defer span.End()
+
+	u, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, apperror.FromDB(err, "user")
+	}
+	return u, nil
 }
 
 // generateOTP creates a random n-digit string.
@@ -103,3 +163,13 @@ func generateOTP(max int) string {
 }
 
 var table = [...]byte{'1', '2', '3', '4', '5', '6', '7', '8', '9', '0'}
+
+// normalizeMobile puts a mobile number in the E.164 form users are stored
+// and OTPs are keyed by, so that 0912…, 0098912… and +98912… are one user.
+func normalizeMobile(raw string) (string, error) {
+	normalized, err := mobile.Normalize(raw)
+	if err != nil {
+		return "", apperror.Invalid("invalid_mobile", err.Error()).Wrap(err)
+	}
+	return normalized, nil
+}
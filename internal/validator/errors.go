@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FieldError describes a field that failed a validation rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+
+	kind reflect.Kind
+}
+
+// FieldErrors lists every invalid field of a request.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, fe := range e {
+		parts[i] = fe.Field + ": " + fe.Rule
+	}
+	return "invalid fields: " + strings.Join(parts, ", ")
+}
+
+// Translate returns a copy of e with messages in locale. Locales without
+// messages fall back to English.
+func (e FieldErrors) Translate(locale string) FieldErrors {
+	lang, _, _ := strings.Cut(strings.ToLower(locale), "-")
+	catalog, ok := messages[lang]
+	if !ok {
+		catalog = messages["en"]
+	}
+	out := make(FieldErrors, len(e))
+	for i, fe := range e {
+		fe.Message = catalog.format(fe)
+		out[i] = fe
+	}
+	return out
+}
+
+// catalog maps rules to message formats; %[1]s is the field and %[2]s the
+// rule's parameter. Size rules have "_len" and "_items" variants for strings
+// and collections, where the parameter is a length rather than a value.
+type catalog map[string]string
+
+func (c catalog) format(fe FieldError) string {
+	variant := ""
+	switch fe.kind {
+	case reflect.String:
+		variant = "_len"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		variant = "_items"
+	}
+	format, ok := c[fe.Rule+variant]
+	if !ok {
+		format, ok = c[fe.Rule]
+	}
+	if !ok {
+		format = c["default"]
+	}
+	param := fe.Param
+	if fe.Rule == "oneof" {
+		param = strings.Join(strings.Fields(param), ", ")
+	}
+	return fmt.Sprintf(format, fe.Field, param)
+}
+
+var messages = map[string]catalog{
+	"en": {
+		"default":   "%[1]s is invalid",
+		"required":  "%[1]s is required",
+		"notempty":  "%[1]s must not be empty",
+		"email":     "%[1]s must be a valid email address",
+		"min":       "%[1]s must be at least %[2]s",
+		"min_len":   "%[1]s must be at least %[2]s characters long",
+		"max":       "%[1]s must be at most %[2]s",
+		"max_len":   "%[1]s must be at most %[2]s characters long",
+		"len":       "%[1]s must be %[2]s",
+		"len_len":   "%[1]s must be exactly %[2]s characters long",
+		"min_items": "%[1]s must have at least %[2]s items",
+		"max_items": "%[1]s must have at most %[2]s items",
+		"gt":        "%[1]s must be greater than %[2]s",
+		"gte":       "%[1]s must be %[2]s or greater",
+		"lte":       "%[1]s must be %[2]s or less",
+		"oneof":     "%[1]s must be one of: %[2]s",
+		"numeric":   "%[1]s must contain only digits",
+		"boolean":   "%[1]s must be true or false",
+		"datetime":  "%[1]s must be an RFC 3339 timestamp",
+		"uuid":      "%[1]s must be a valid UUID",
+		"uuid_list": "%[1]s must be a list of valid UUIDs",
+		"mobile":    "%[1]s must be an Iranian or E.164 mobile number",
+		"instagram": "%[1]s must be an Instagram profile link",
+		"unique":    "%[1]s must not contain duplicates",
+	},
+	"fa": {
+		"default":   "%[1]s نامعتبر است",
+		"required":  "%[1]s الزامی است",
+		"notempty":  "%[1]s نباید خالی باشد",
+		"email":     "%[1]s باید یک نشانی ایمیل معتبر باشد",
+		"min":       "%[1]s باید دست‌کم %[2]s باشد",
+		"min_len":   "%[1]s باید دست‌کم %[2]s نویسه باشد",
+		"max":       "%[1]s باید حداکثر %[2]s باشد",
+		"max_len":   "%[1]s باید حداکثر %[2]s نویسه باشد",
+		"len":       "%[1]s باید %[2]s باشد",
+		"len_len":   "%[1]s باید دقیقاً %[2]s نویسه باشد",
+		"min_items": "%[1]s باید دست‌کم %[2]s مورد داشته باشد",
+		"max_items": "%[1]s باید حداکثر %[2]s مورد داشته باشد",
+		"gt":        "%[1]s باید بزرگ‌تر از %[2]s باشد",
+		"gte":       "%[1]s باید %[2]s یا بیشتر باشد",
+		"lte":       "%[1]s باید %[2]s یا کمتر باشد",
+		"oneof":     "%[1]s باید یکی از این مقادیر باشد: %[2]s",
+		"numeric":   "%[1]s فقط باید شامل رقم باشد",
+		"boolean":   "%[1]s باید true یا false باشد",
+		"datetime":  "%[1]s باید یک زمان با قالب RFC 3339 باشد",
+		"uuid":      "%[1]s باید یک UUID معتبر باشد",
+		"uuid_list": "%[1]s باید فهرستی از UUIDهای معتبر باشد",
+		"mobile":    "%[1]s باید یک شماره موبایل ایرانی یا E.164 باشد",
+		"instagram": "%[1]s باید پیوند یک صفحه اینستاگرام باشد",
+		"unique":    "%[1]s نباید مقدار تکراری داشته باشد",
+	},
+}
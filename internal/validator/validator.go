@@ -1,15 +1,120 @@
 package validator
 
-import "github.com/go-playground/validator/v10"
+import (
+	"errors"
+	"reflect"
+	"strings"
 
+	"github.com/cavidyrm/instawall/internal/apperror"
+	"github.com/cavidyrm/instawall/pkg/instagram"
+	"github.com/cavidyrm/instawall/pkg/mobile"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// CustomValidator validates request structs for Echo. Besides the built-in
+// rules it knows "mobile", "instagram", "uuid_list" and "notempty". The last
+// one is for optional pointer fields, where "required" only checks that the
+// field was sent.
 type CustomValidator struct {
 	validator *validator.Validate
 }
 
 func NewValidator() *CustomValidator {
-	return &CustomValidator{validator: validator.New()}
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(fieldName)
+	// Registration only fails for empty tags or nil functions.
+	_ = v.RegisterValidation("mobile", isMobile)
+	_ = v.RegisterValidation("instagram", isInstagramLink)
+	_ = v.RegisterValidation("uuid_list", isUUIDList)
+	v.RegisterAlias("notempty", "min=1")
+	return &CustomValidator{validator: v}
 }
 
+// Validate checks a struct against its validate tags. Failures are returned
+// as an unprocessable apperror wrapping FieldErrors.
 func (cv *CustomValidator) Validate(i interface{}) error {
-	return cv.validator.Struct(i)
+	err := cv.validator.Struct(i)
+	var invalid validator.ValidationErrors
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	fields := make(FieldErrors, 0, len(invalid))
+	for _, fe := range invalid {
+		param := fe.Param()
+		if fe.Tag() != fe.ActualTag() {
+			// Aliases hide the parameters of the rules they stand for.
+			param = ""
+		}
+		fields = append(fields, FieldError{
+			Field: fieldPath(fe.Namespace()),
+			Rule:  fe.Tag(),
+			Param: param,
+			kind:  fe.Kind(),
+		})
+	}
+	return apperror.Unprocessable("validation_failed", "request validation failed").Wrap(fields)
+}
+
+// fieldName reports fields by the name clients send them as.
+func fieldName(f reflect.StructField) string {
+	for _, key := range []string{"json", "form", "query"} {
+		name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// fieldPath drops the struct name from a namespace such as
+// "ReorderRequest.order[0].id".
+func fieldPath(namespace string) string {
+	_, path, ok := strings.Cut(namespace, ".")
+	if !ok {
+		return namespace
+	}
+	return path
+}
+
+func isMobile(fl validator.FieldLevel) bool {
+	return mobile.Valid(fl.Field().String())
+}
+
+func isInstagramLink(fl validator.FieldLevel) bool {
+	_, err := instagram.ParseHandle(fl.Field().String())
+	return err == nil
+}
+
+// isUUIDList accepts a comma-separated string or a slice of strings in which
+// every element is a UUID.
+func isUUIDList(fl validator.FieldLevel) bool {
+	field := fl.Field()
+	switch field.Kind() {
+	case reflect.String:
+		if field.String() == "" {
+			return true
+		}
+		for _, s := range strings.Split(field.String(), ",") {
+			if _, err := uuid.Parse(strings.TrimSpace(s)); err != nil {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice:
+		for i := 0; i < field.Len(); i++ {
+			elem := field.Index(i)
+			if elem.Kind() != reflect.String {
+				return false
+			}
+			if _, err := uuid.Parse(elem.String()); err != nil {
+				return false
+			}
+		}
+		return true
+	}
+	return false
 }
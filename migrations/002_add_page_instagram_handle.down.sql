@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_pages_instagram_handle;
+ALTER TABLE pages DROP COLUMN IF EXISTS instagram_handle;
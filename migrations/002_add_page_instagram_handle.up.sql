@@ -0,0 +1,25 @@
+-- Store the canonical Instagram username of each page so the same account
+-- cannot be listed more than once.
+ALTER TABLE pages ADD COLUMN instagram_handle VARCHAR(30);
+
+-- Backfill handles from existing links. When several pages point to the same
+-- account only the oldest one claims the handle; the rest are left NULL.
+UPDATE pages p
+SET instagram_handle = d.handle
+FROM (
+    SELECT DISTINCT ON (handle) id, handle
+    FROM (
+        SELECT id,
+               created_at,
+               lower(COALESCE(
+                   substring(link from '^(?:[Hh][Tt][Tt][Pp][Ss]?://)?(?:[Ww][Ww][Ww]\.|[Mm]\.)?[Ii][Nn][Ss][Tt][Aa][Gg][Rr][Aa][Mm]\.[Cc][Oo][Mm]/([A-Za-z0-9._]{1,30})(?:[/?#].*)?$'),
+                   substring(link from '^@([A-Za-z0-9._]{1,30})$')
+               )) AS handle
+        FROM pages
+    ) s
+    WHERE handle IS NOT NULL
+    ORDER BY handle, created_at
+) d
+WHERE p.id = d.id;
+
+CREATE UNIQUE INDEX idx_pages_instagram_handle ON pages(instagram_handle);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_pages_link_next_check_at;
+ALTER TABLE pages
+    DROP COLUMN IF EXISTS link_next_check_at,
+    DROP COLUMN IF EXISTS link_failure_streak,
+    DROP COLUMN IF EXISTS link_status_code,
+    DROP COLUMN IF EXISTS link_checked_at;
@@ -0,0 +1,9 @@
+-- Track the health of each page's Instagram link as observed by the
+-- background link checker.
+ALTER TABLE pages
+    ADD COLUMN link_checked_at TIMESTAMPTZ,
+    ADD COLUMN link_status_code INT,
+    ADD COLUMN link_failure_streak INT NOT NULL DEFAULT 0,
+    ADD COLUMN link_next_check_at TIMESTAMPTZ;
+
+CREATE INDEX idx_pages_link_next_check_at ON pages(link_next_check_at NULLS FIRST);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS page_claims;
+ALTER TABLE pages DROP COLUMN IF EXISTS verified;
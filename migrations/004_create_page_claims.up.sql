@@ -0,0 +1,18 @@
+ALTER TABLE pages ADD COLUMN verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- A claim is a request by a user to prove they control a page's Instagram
+-- account by placing a generated code in its bio.
+CREATE TABLE page_claims (
+                             id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                             page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                             user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                             code VARCHAR(32) NOT NULL,
+                             status VARCHAR(20) NOT NULL DEFAULT 'pending',
+                             attempts INT NOT NULL DEFAULT 0,
+                             previous_owner_id UUID REFERENCES users(id) ON DELETE SET NULL,
+                             expires_at TIMESTAMPTZ NOT NULL,
+                             verified_at TIMESTAMPTZ,
+                             created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_page_claims_page_id ON page_claims(page_id);
+CREATE UNIQUE INDEX idx_page_claims_pending ON page_claims(page_id, user_id) WHERE status = 'pending';
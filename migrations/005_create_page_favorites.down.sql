@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS update_pages_updated_at ON pages;
+CREATE TRIGGER update_pages_updated_at
+    BEFORE UPDATE ON pages
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE pages DROP COLUMN IF EXISTS favorite_count;
+DROP TABLE IF EXISTS page_favorites;
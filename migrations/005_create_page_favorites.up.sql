@@ -0,0 +1,22 @@
+CREATE TABLE page_favorites (
+                                user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                                page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                                created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                PRIMARY KEY (user_id, page_id)
+);
+CREATE INDEX idx_page_favorites_page_id ON page_favorites(page_id);
+CREATE INDEX idx_page_favorites_user_created ON page_favorites(user_id, created_at DESC);
+
+-- Denormalized so listings don't have to count favorites per page.
+ALTER TABLE pages ADD COLUMN favorite_count INT NOT NULL DEFAULT 0;
+UPDATE pages p SET favorite_count = f.cnt
+FROM (SELECT page_id, COUNT(*) AS cnt FROM page_favorites GROUP BY page_id) f
+WHERE p.id = f.page_id;
+
+-- Only edits to page content should bump updated_at, not counters or
+-- link-checker bookkeeping.
+DROP TRIGGER IF EXISTS update_pages_updated_at ON pages;
+CREATE TRIGGER update_pages_updated_at
+    BEFORE UPDATE OF user_id, title, description, image_url, link, instagram_handle, has_issue, verified ON pages
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
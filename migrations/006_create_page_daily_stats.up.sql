@@ -0,0 +1,10 @@
+-- Daily view and click totals per page, flushed in batches from the Redis
+-- event buffer.
+CREATE TABLE page_daily_stats (
+                                  page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                                  day DATE NOT NULL,
+                                  views BIGINT NOT NULL DEFAULT 0,
+                                  clicks BIGINT NOT NULL DEFAULT 0,
+                                  PRIMARY KEY (page_id, day)
+);
+CREATE INDEX idx_page_daily_stats_day ON page_daily_stats(day);
@@ -0,0 +1,13 @@
+-- Admin-curated featured pages, shown in position order while active.
+CREATE TABLE featured_pages (
+                                id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                                page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                                position INT NOT NULL DEFAULT 0,
+                                starts_at TIMESTAMPTZ,
+                                ends_at TIMESTAMPTZ,
+                                created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                                created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                CHECK (ends_at IS NULL OR starts_at IS NULL OR ends_at > starts_at)
+);
+CREATE INDEX idx_featured_pages_page_id ON featured_pages(page_id);
+CREATE INDEX idx_featured_pages_window ON featured_pages(starts_at, ends_at);
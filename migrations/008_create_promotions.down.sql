@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS promotion_categories;
+DROP TRIGGER IF EXISTS update_promotions_updated_at ON promotions;
+DROP TABLE IF EXISTS promotions;
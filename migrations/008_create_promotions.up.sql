@@ -0,0 +1,32 @@
+-- Paid placements of a page in page listings. A promotion without target
+-- categories runs in every listing.
+CREATE TABLE promotions (
+                            id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                            page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                            requested_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+                            starts_at TIMESTAMPTZ NOT NULL,
+                            ends_at TIMESTAMPTZ NOT NULL,
+                            priority INT NOT NULL DEFAULT 0,
+                            status VARCHAR(20) NOT NULL DEFAULT 'pending',
+                            payment_reference VARCHAR(255),
+                            reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+                            reviewed_at TIMESTAMPTZ,
+                            impressions BIGINT NOT NULL DEFAULT 0,
+                            clicks BIGINT NOT NULL DEFAULT 0,
+                            created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                            updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                            CHECK (ends_at > starts_at)
+);
+CREATE INDEX idx_promotions_active ON promotions(status, starts_at, ends_at);
+CREATE INDEX idx_promotions_page_id ON promotions(page_id);
+CREATE TRIGGER update_promotions_updated_at
+    BEFORE UPDATE OF page_id, starts_at, ends_at, priority, status, payment_reference ON promotions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE promotion_categories (
+                                      promotion_id UUID NOT NULL REFERENCES promotions(id) ON DELETE CASCADE,
+                                      category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+                                      PRIMARY KEY (promotion_id, category_id)
+);
+CREATE INDEX idx_promotion_categories_category_id ON promotion_categories(category_id);
@@ -0,0 +1,18 @@
+ALTER TABLE categories ADD COLUMN parent_id UUID REFERENCES categories(id) ON DELETE RESTRICT;
+ALTER TABLE categories ADD COLUMN slug VARCHAR(120);
+
+-- Best-effort slugs for existing categories. Titles without Latin letters or
+-- digits fall back to an ID-based slug; admins can set a better one later.
+UPDATE categories
+SET slug = NULLIF(trim(BOTH '-' FROM lower(regexp_replace(title, '[^A-Za-z0-9]+', '-', 'g'))), '');
+UPDATE categories SET slug = 'category-' || left(id::text, 8) WHERE slug IS NULL;
+UPDATE categories c
+SET slug = c.slug || '-' || left(c.id::text, 8)
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY slug ORDER BY created_at) AS n FROM categories
+) d
+WHERE c.id = d.id AND d.n > 1;
+
+ALTER TABLE categories ALTER COLUMN slug SET NOT NULL;
+CREATE UNIQUE INDEX idx_categories_slug ON categories(slug);
+CREATE INDEX idx_categories_parent_id ON categories(parent_id);
@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS count_pages_deleted ON pages;
+DROP TRIGGER IF EXISTS count_pages_listing ON pages;
+DROP TRIGGER IF EXISTS count_page_categories ON page_categories;
+DROP FUNCTION IF EXISTS count_page_deleted();
+DROP FUNCTION IF EXISTS count_page_listing_change();
+DROP FUNCTION IF EXISTS count_page_category_change();
+DROP FUNCTION IF EXISTS page_is_listed(pages);
+DROP INDEX IF EXISTS idx_categories_sort_order;
+ALTER TABLE categories DROP COLUMN IF EXISTS page_count;
+ALTER TABLE categories DROP COLUMN IF EXISTS is_active;
+ALTER TABLE categories DROP COLUMN IF EXISTS sort_order;
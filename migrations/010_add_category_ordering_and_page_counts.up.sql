@@ -0,0 +1,84 @@
+ALTER TABLE categories ADD COLUMN sort_order INT NOT NULL DEFAULT 0;
+ALTER TABLE categories ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE categories ADD COLUMN page_count INT NOT NULL DEFAULT 0;
+
+-- Keep the current alphabetical order as the initial manual order.
+UPDATE categories c SET sort_order = o.n
+FROM (SELECT id, row_number() OVER (PARTITION BY parent_id ORDER BY title) AS n FROM categories) o
+WHERE c.id = o.id;
+CREATE INDEX idx_categories_sort_order ON categories(parent_id, sort_order);
+
+-- page_count counts the listed pages (those without a reported issue) that
+-- are directly in a category. It is kept up to date by the triggers below.
+CREATE OR REPLACE FUNCTION page_is_listed(p pages) RETURNS BOOLEAN AS $$
+    SELECT NOT p.has_issue;
+$$ LANGUAGE sql STABLE;
+
+UPDATE categories c SET page_count = pc.cnt
+FROM (
+    SELECT pc.category_id, COUNT(*) AS cnt
+    FROM page_categories pc JOIN pages p ON p.id = pc.page_id
+    WHERE page_is_listed(p)
+    GROUP BY pc.category_id
+) pc
+WHERE c.id = pc.category_id;
+
+-- Adding or removing a category of a page. When the page itself is being
+-- deleted it is no longer visible here; count_page_deleted handles that.
+CREATE OR REPLACE FUNCTION count_page_category_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE categories SET page_count = page_count + 1
+        WHERE id = NEW.category_id
+          AND EXISTS (SELECT 1 FROM pages p WHERE p.id = NEW.page_id AND page_is_listed(p));
+    ELSE
+        UPDATE categories SET page_count = page_count - 1
+        WHERE id = OLD.category_id
+          AND EXISTS (SELECT 1 FROM pages p WHERE p.id = OLD.page_id AND page_is_listed(p));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER count_page_categories
+    AFTER INSERT OR DELETE ON page_categories
+    FOR EACH ROW
+    EXECUTE FUNCTION count_page_category_change();
+
+-- A page becoming listed or unlisted.
+CREATE OR REPLACE FUNCTION count_page_listing_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    delta INT;
+BEGIN
+    IF page_is_listed(NEW) = page_is_listed(OLD) THEN
+        RETURN NULL;
+    END IF;
+    delta := CASE WHEN page_is_listed(NEW) THEN 1 ELSE -1 END;
+    UPDATE categories SET page_count = page_count + delta
+    WHERE id IN (SELECT category_id FROM page_categories WHERE page_id = NEW.id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER count_pages_listing
+    AFTER UPDATE ON pages
+    FOR EACH ROW
+    EXECUTE FUNCTION count_page_listing_change();
+
+CREATE OR REPLACE FUNCTION count_page_deleted()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF page_is_listed(OLD) THEN
+        UPDATE categories SET page_count = page_count - 1
+        WHERE id IN (SELECT category_id FROM page_categories WHERE page_id = OLD.id);
+    END IF;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER count_pages_deleted
+    BEFORE DELETE ON pages
+    FOR EACH ROW
+    EXECUTE FUNCTION count_page_deleted();
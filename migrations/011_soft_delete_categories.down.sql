@@ -0,0 +1,7 @@
+DELETE FROM categories WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_categories_slug;
+CREATE UNIQUE INDEX idx_categories_slug ON categories(slug);
+DROP INDEX IF EXISTS idx_categories_title;
+ALTER TABLE categories ADD CONSTRAINT categories_title_key UNIQUE (title);
+DROP INDEX IF EXISTS idx_categories_deleted_at;
+ALTER TABLE categories DROP COLUMN IF EXISTS deleted_at;
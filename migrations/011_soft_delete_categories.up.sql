@@ -0,0 +1,9 @@
+ALTER TABLE categories ADD COLUMN deleted_at TIMESTAMPTZ;
+CREATE INDEX idx_categories_deleted_at ON categories(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Deleted categories keep their title and slug until they are purged, so
+-- uniqueness only applies among live categories.
+ALTER TABLE categories DROP CONSTRAINT IF EXISTS categories_title_key;
+CREATE UNIQUE INDEX idx_categories_title ON categories(title) WHERE deleted_at IS NULL;
+DROP INDEX IF EXISTS idx_categories_slug;
+CREATE UNIQUE INDEX idx_categories_slug ON categories(slug) WHERE deleted_at IS NULL;
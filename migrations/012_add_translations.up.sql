@@ -0,0 +1,18 @@
+-- The title and description on categories and pages are in the default
+-- locale; these tables hold the other locales.
+CREATE TABLE category_translations (
+                                       category_id UUID NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+                                       locale VARCHAR(35) NOT NULL,
+                                       title VARCHAR(100) NOT NULL,
+                                       description TEXT NOT NULL DEFAULT '',
+                                       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                       PRIMARY KEY (category_id, locale)
+);
+
+CREATE TABLE page_translations (
+                                   page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                                   locale VARCHAR(35) NOT NULL,
+                                   description TEXT NOT NULL,
+                                   updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+                                   PRIMARY KEY (page_id, locale)
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS page_tags;
+DROP FUNCTION IF EXISTS count_page_tag_change();
+DROP TABLE IF EXISTS tags;
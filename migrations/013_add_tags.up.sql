@@ -0,0 +1,34 @@
+CREATE TABLE tags (
+                      id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                      name VARCHAR(50) UNIQUE NOT NULL, -- normalized, see tag/domain.Normalize
+                      banned BOOLEAN NOT NULL DEFAULT FALSE,
+                      merged_into UUID REFERENCES tags(id) ON DELETE SET NULL, -- set on tags merged into another
+                      page_count INT NOT NULL DEFAULT 0,
+                      created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- Supports prefix search with LIKE 'abc%'.
+CREATE INDEX idx_tags_name_prefix ON tags(name text_pattern_ops);
+
+CREATE TABLE page_tags (
+                           page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                           tag_id UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+                           PRIMARY KEY (page_id, tag_id)
+);
+CREATE INDEX idx_page_tags_tag_id ON page_tags(tag_id);
+
+CREATE OR REPLACE FUNCTION count_page_tag_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        UPDATE tags SET page_count = page_count + 1 WHERE id = NEW.tag_id;
+    ELSE
+        UPDATE tags SET page_count = page_count - 1 WHERE id = OLD.tag_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER count_page_tags
+    AFTER INSERT OR DELETE ON page_tags
+    FOR EACH ROW
+    EXECUTE FUNCTION count_page_tag_change();
@@ -0,0 +1,15 @@
+CREATE TABLE page_images (
+                             id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+                             page_id UUID NOT NULL REFERENCES pages(id) ON DELETE CASCADE,
+                             url VARCHAR(255) NOT NULL,
+                             position INT NOT NULL DEFAULT 0,
+                             is_cover BOOLEAN NOT NULL DEFAULT FALSE,
+                             created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_page_images_page_position ON page_images(page_id, position);
+CREATE UNIQUE INDEX idx_page_images_cover ON page_images(page_id) WHERE is_cover;
+
+-- Every existing image becomes its page's cover. pages.image_url is kept as
+-- a copy of the cover URL so listings don't need to join page_images.
+INSERT INTO page_images (page_id, url, position, is_cover)
+SELECT id, image_url, 0, TRUE FROM pages WHERE image_url IS NOT NULL AND image_url <> '';
@@ -0,0 +1,27 @@
+DROP TRIGGER IF EXISTS update_pages_updated_at ON pages;
+CREATE TRIGGER update_pages_updated_at
+    BEFORE UPDATE OF user_id, title, description, image_url, link, instagram_handle, has_issue, verified ON pages
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE OR REPLACE FUNCTION page_is_listed(p pages) RETURNS BOOLEAN AS $$
+    SELECT NOT p.has_issue;
+$$ LANGUAGE sql STABLE;
+
+UPDATE categories c SET page_count = COALESCE(pc.cnt, 0)
+FROM categories c2
+LEFT JOIN (
+    SELECT pc.category_id, COUNT(*) AS cnt
+    FROM page_categories pc JOIN pages p ON p.id = pc.page_id
+    WHERE page_is_listed(p)
+    GROUP BY pc.category_id
+) pc ON pc.category_id = c2.id
+WHERE c.id = c2.id;
+
+DROP INDEX IF EXISTS idx_pages_published_at;
+DROP INDEX IF EXISTS idx_pages_user_status;
+DROP INDEX IF EXISTS idx_pages_publish_due;
+ALTER TABLE pages DROP CONSTRAINT IF EXISTS pages_scheduled_publish_at;
+ALTER TABLE pages DROP COLUMN IF EXISTS published_at;
+ALTER TABLE pages DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE pages DROP COLUMN IF EXISTS status;
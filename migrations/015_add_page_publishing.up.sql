@@ -0,0 +1,34 @@
+-- Pages can be kept as drafts or scheduled to be published at publish_at.
+-- Existing pages are published as of their creation.
+ALTER TABLE pages ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published'
+    CHECK (status IN ('draft', 'scheduled', 'published'));
+ALTER TABLE pages ADD COLUMN publish_at TIMESTAMPTZ;
+ALTER TABLE pages ADD COLUMN published_at TIMESTAMPTZ;
+UPDATE pages SET published_at = created_at;
+ALTER TABLE pages ADD CONSTRAINT pages_scheduled_publish_at
+    CHECK (status <> 'scheduled' OR publish_at IS NOT NULL);
+
+CREATE INDEX idx_pages_publish_due ON pages(publish_at) WHERE status = 'scheduled';
+CREATE INDEX idx_pages_user_status ON pages(user_id, status);
+CREATE INDEX idx_pages_published_at ON pages(published_at DESC) WHERE status = 'published';
+
+-- Only published pages are listed and counted in categories.
+CREATE OR REPLACE FUNCTION page_is_listed(p pages) RETURNS BOOLEAN AS $$
+    SELECT NOT p.has_issue AND p.status = 'published';
+$$ LANGUAGE sql STABLE;
+
+UPDATE categories c SET page_count = COALESCE(pc.cnt, 0)
+FROM categories c2
+LEFT JOIN (
+    SELECT pc.category_id, COUNT(*) AS cnt
+    FROM page_categories pc JOIN pages p ON p.id = pc.page_id
+    WHERE page_is_listed(p)
+    GROUP BY pc.category_id
+) pc ON pc.category_id = c2.id
+WHERE c.id = c2.id;
+
+DROP TRIGGER IF EXISTS update_pages_updated_at ON pages;
+CREATE TRIGGER update_pages_updated_at
+    BEFORE UPDATE OF user_id, title, description, image_url, link, instagram_handle, has_issue, verified, status, publish_at ON pages
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
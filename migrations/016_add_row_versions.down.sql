@@ -0,0 +1,13 @@
+DROP TRIGGER IF EXISTS bump_category_version_on_translations ON category_translations;
+DROP TRIGGER IF EXISTS bump_page_version_on_translations ON page_translations;
+DROP TRIGGER IF EXISTS bump_page_version_on_tags ON page_tags;
+DROP TRIGGER IF EXISTS bump_page_version_on_images ON page_images;
+DROP FUNCTION IF EXISTS bump_category_version();
+DROP FUNCTION IF EXISTS bump_page_version();
+
+DROP TRIGGER IF EXISTS bump_categories_version ON categories;
+DROP TRIGGER IF EXISTS bump_pages_version ON pages;
+DROP FUNCTION IF EXISTS bump_row_version();
+
+ALTER TABLE categories DROP COLUMN IF EXISTS version;
+ALTER TABLE pages DROP COLUMN IF EXISTS version;
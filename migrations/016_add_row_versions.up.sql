@@ -0,0 +1,82 @@
+-- Row versions for optimistic concurrency control. They are sent as ETags
+-- and checked against If-Match, so that concurrent edits are rejected
+-- instead of overwriting each other.
+ALTER TABLE pages ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE categories ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_row_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Only changes to what clients edit or see bump the version. Counters
+-- (favorite_count, page_count) and link check bookkeeping are left out, and
+-- updates that write the same values again don't count.
+CREATE TRIGGER bump_pages_version
+    BEFORE UPDATE ON pages
+    FOR EACH ROW
+    WHEN ((OLD.user_id, OLD.title, OLD.description, OLD.image_url, OLD.link, OLD.instagram_handle,
+           OLD.has_issue, OLD.verified, OLD.status, OLD.publish_at)
+          IS DISTINCT FROM
+          (NEW.user_id, NEW.title, NEW.description, NEW.image_url, NEW.link, NEW.instagram_handle,
+           NEW.has_issue, NEW.verified, NEW.status, NEW.publish_at))
+    EXECUTE FUNCTION bump_row_version();
+
+CREATE TRIGGER bump_categories_version
+    BEFORE UPDATE ON categories
+    FOR EACH ROW
+    WHEN ((OLD.parent_id, OLD.title, OLD.slug, OLD.description, OLD.image_url, OLD.sort_order,
+           OLD.is_active, OLD.deleted_at)
+          IS DISTINCT FROM
+          (NEW.parent_id, NEW.title, NEW.slug, NEW.description, NEW.image_url, NEW.sort_order,
+           NEW.is_active, NEW.deleted_at))
+    EXECUTE FUNCTION bump_row_version();
+
+-- Images, tags and translations are part of a page's or category's
+-- representation, so changing them bumps the parent's version too.
+CREATE OR REPLACE FUNCTION bump_page_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE pages SET version = version + 1 WHERE id = OLD.page_id;
+    ELSE
+        UPDATE pages SET version = version + 1 WHERE id = NEW.page_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION bump_category_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE categories SET version = version + 1 WHERE id = OLD.category_id;
+    ELSE
+        UPDATE categories SET version = version + 1 WHERE id = NEW.category_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bump_page_version_on_images
+    AFTER INSERT OR UPDATE OR DELETE ON page_images
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_page_version();
+
+CREATE TRIGGER bump_page_version_on_tags
+    AFTER INSERT OR UPDATE OR DELETE ON page_tags
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_page_version();
+
+CREATE TRIGGER bump_page_version_on_translations
+    AFTER INSERT OR UPDATE OR DELETE ON page_translations
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_page_version();
+
+CREATE TRIGGER bump_category_version_on_translations
+    AFTER INSERT OR UPDATE OR DELETE ON category_translations
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_category_version();
@@ -0,0 +1,19 @@
+-- Mobile numbers are stored in E.164 form. Iranian numbers used to be stored
+-- as they were entered (0912…, 912…, 0098912… or +98912…), so the same number
+-- could belong to several users. Each number is rewritten unless another user
+-- already holds its E.164 form; of several rows for one number only the
+-- oldest is rewritten, and the others are left for manual review.
+WITH canonical AS (
+    SELECT id,
+           '+98' || right(mobile_number, 10) AS normalized,
+           row_number() OVER (PARTITION BY right(mobile_number, 10) ORDER BY created_at, id) AS n
+    FROM users
+    WHERE mobile_number ~ '^(\+98|0098|0)?9[0-9]{9}$'
+)
+UPDATE users u
+SET mobile_number = c.normalized
+FROM canonical c
+WHERE u.id = c.id
+  AND c.n = 1
+  AND u.mobile_number <> c.normalized
+  AND NOT EXISTS (SELECT 1 FROM users o WHERE o.mobile_number = c.normalized);
@@ -0,0 +1 @@
+DROP TRIGGER IF EXISTS bump_page_version_on_categories ON page_categories;
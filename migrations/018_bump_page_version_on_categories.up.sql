@@ -0,0 +1,6 @@
+-- A page's categories are part of its representation too, so changing them
+-- bumps its version like images, tags and translations do.
+CREATE TRIGGER bump_page_version_on_categories
+    AFTER INSERT OR UPDATE OR DELETE ON page_categories
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_page_version();
@@ -0,0 +1,10 @@
+// Package migrations embeds the SQL migrations so that they ship inside the
+// binary.
+package migrations
+
+import "embed"
+
+// FS holds the NNN_name.up.sql and NNN_name.down.sql files.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -3,24 +3,33 @@ package database
 
 import (
 	"fmt"
+
+	"github.com/XSAM/otelsql"
 	"github.com/cavidyrm/instawall/config"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
 )
 
-// NewPostgresDB creates a new PostgreSQL database connection.
+// NewPostgresDB creates a new PostgreSQL database connection. Queries are
+// traced with OpenTelemetry.
 func NewPostgresDB(cfg config.PostgresConfig) (*sqlx.DB, error) {
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode,
 	)
 
-	db, err := sqlx.Connect("postgres", dsn)
+	sqlDB, err := otelsql.Open("postgres", dsn,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBNamespace(cfg.DBName)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		return nil, err
 	}
+	db := sqlx.NewDb(sqlDB, "postgres")
 
 	// Ping the database to verify the connection is alive.
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, err
 	}
 
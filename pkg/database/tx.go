@@ -0,0 +1,19 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Rollback rolls tx back, to be deferred right after it is begun. Once the
+// transaction is committed this does nothing; other failures are logged, as
+// the error that caused the rollback has already been returned.
+func Rollback(ctx context.Context, logger *slog.Logger, tx *sqlx.Tx) {
+	if err := tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		logger.WarnContext(ctx, "roll back transaction", "err", err)
+	}
+}
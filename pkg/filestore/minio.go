@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"path"
 	"path/filepath"
 	"time"
 
@@ -11,8 +12,15 @@ import (
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
 	"github.com/cavidyrm/instawall/config" // <-- Replace with your module name
+	"github.com/cavidyrm/instawall/internal/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/cavidyrm/instawall/pkg/filestore")
+
 // FileStore handles file upload operations.
 type FileStore struct {
 	client     *minio.Client
@@ -55,10 +63,16 @@ func (fs *FileStore) UploadFile(ctx context.Context, file io.Reader, fileSize in
 	uniqueFilename := fmt.Sprintf("%s-%s%s", time.Now().Format("20060102"), uuid.New().String(), ext)
 
 	// Upload the file.
+	ctx, span := fs.startSpan(ctx, "minio.PutObject", uniqueFilename)
+	span.SetAttributes(attribute.Int64("minio.object.size", fileSize))
+	start := time.Now()
 	_, err := fs.client.PutObject(ctx, fs.bucketName, uniqueFilename, file, fileSize, minio.PutObjectOptions{})
+	metrics.StorageUploadDuration.WithLabelValues(metrics.Result(err)).Observe(time.Since(start).Seconds())
+	endSpan(span, err)
 	if err != nil {
 		return "", err
 	}
+	metrics.StorageUploadSize.Observe(float64(fileSize))
 
 	// Construct the URL. The URL format depends on your MinIO setup and domain.
 	// This is a typical format for a local setup.
@@ -66,3 +80,38 @@ func (fs *FileStore) UploadFile(ctx context.Context, file io.Reader, fileSize in
 
 	return url, nil
 }
+
+// DeleteFile removes a file previously returned by UploadFile.
+func (fs *FileStore) DeleteFile(ctx context.Context, url string) error {
+	ctx, span := fs.startSpan(ctx, "minio.RemoveObject", path.Base(url))
+	err := fs.client.RemoveObject(ctx, fs.bucketName, path.Base(url), minio.RemoveObjectOptions{})
+	endSpan(span, err)
+	return err
+}
+
+// Ping checks that MinIO is reachable and the bucket exists.
+func (fs *FileStore) Ping(ctx context.Context) error {
+	exists, err := fs.client.BucketExists(ctx, fs.bucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("bucket %q does not exist", fs.bucketName)
+	}
+	return nil
+}
+
+func (fs *FileStore) startSpan(ctx context.Context, name, object string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("minio.bucket", fs.bucketName),
+		attribute.String("minio.object", object),
+	))
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Negotiator picks the best supported locale for a request.
+type Negotiator struct {
+	locales []string // canonical names, default first
+	matcher language.Matcher
+}
+
+// NewNegotiator creates a Negotiator for the supported locales. The default
+// locale is used when nothing the client asks for is supported; it is added
+// to the supported locales if missing.
+func NewNegotiator(defaultLocale string, supported []string) (*Negotiator, error) {
+	def, err := language.Parse(defaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default locale %q: %w", defaultLocale, err)
+	}
+	tags := []language.Tag{def}
+	for _, s := range supported {
+		t, err := language.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid locale %q: %w", s, err)
+		}
+		if t != def {
+			tags = append(tags, t)
+		}
+	}
+
+	n := &Negotiator{matcher: language.NewMatcher(tags)}
+	for _, t := range tags {
+		n.locales = append(n.locales, t.String())
+	}
+	return n, nil
+}
+
+// Default returns the default locale.
+func (n *Negotiator) Default() string {
+	return n.locales[0]
+}
+
+// Negotiate returns the supported locale that best matches an explicitly
+// requested locale, such as a "lang" query parameter, or failing that an
+// Accept-Language header. Either may be empty.
+func (n *Negotiator) Negotiate(requested, acceptLanguage string) string {
+	if requested != "" {
+		if t, err := language.Parse(requested); err == nil {
+			if _, i, conf := n.matcher.Match(t); conf != language.No {
+				return n.locales[i]
+			}
+		}
+	}
+	if acceptLanguage != "" {
+		if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+			if _, i, conf := n.matcher.Match(tags...); conf != language.No {
+				return n.locales[i]
+			}
+		}
+	}
+	return n.Default()
+}
+
+// Normalize returns the canonical form of a BCP 47 locale such as "fa" or
+// "en-US".
+func Normalize(locale string) (string, error) {
+	t, err := language.Parse(locale)
+	if err != nil {
+		return "", fmt.Errorf("invalid locale %q", locale)
+	}
+	return t.String(), nil
+}
@@ -0,0 +1,50 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// BioVerifier checks for a verification code on an account's public
+// profile page.
+type BioVerifier struct {
+	client  *http.Client
+	baseURL string
+}
+
+// NewBioVerifier creates a BioVerifier. baseURL defaults to the public
+// Instagram site and may point to a stand-in server.
+func NewBioVerifier(client *http.Client, baseURL string) *BioVerifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if baseURL == "" {
+		baseURL = "https://www.instagram.com"
+	}
+	return &BioVerifier{client: client, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// BioContains reports whether code appears on the profile page of handle.
+func (v *BioVerifier) BioContains(ctx context.Context, handle, code string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.baseURL+"/"+handle+"/", nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("fetch profile @%s: unexpected status %d", handle, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 2<<20))
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(string(body), code), nil
+}
@@ -0,0 +1,100 @@
+package instagram
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// handlePattern matches the characters Instagram allows in a username.
+var handlePattern = regexp.MustCompile(`^[a-z0-9._]{1,30}$`)
+
+// reservedPaths are first path segments on instagram.com that are not profiles.
+var reservedPaths = map[string]bool{
+	"p": true, "reel": true, "reels": true, "tv": true, "stories": true,
+	"explore": true, "accounts": true, "direct": true, "about": true,
+	"developer": true, "legal": true, "web": true,
+}
+
+// LinkError describes why a link could not be parsed as an Instagram profile.
+type LinkError struct {
+	Link   string
+	Reason string
+}
+
+func (e *LinkError) Error() string {
+	return fmt.Sprintf("invalid instagram link %q: %s", e.Link, e.Reason)
+}
+
+// ParseHandle extracts the canonical (lower-cased) Instagram username from a
+// profile URL, a bare domain path like "instagram.com/x", or an "@x" handle.
+func ParseHandle(raw string) (string, error) {
+	link := strings.TrimSpace(raw)
+	if link == "" {
+		return "", &LinkError{Link: raw, Reason: "link is empty"}
+	}
+
+	var handle string
+	if strings.HasPrefix(link, "@") || isBareHandle(link) {
+		handle = strings.TrimPrefix(link, "@")
+	} else {
+		if !strings.Contains(link, "://") {
+			link = "https://" + link
+		}
+		u, err := url.Parse(link)
+		if err != nil {
+			return "", &LinkError{Link: raw, Reason: "link is not a valid URL"}
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return "", &LinkError{Link: raw, Reason: "link must use http or https"}
+		}
+		if !isInstagramHost(u.Hostname()) {
+			return "", &LinkError{Link: raw, Reason: "link does not point to instagram.com"}
+		}
+		segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+		if segments[0] == "" {
+			return "", &LinkError{Link: raw, Reason: "link does not contain a username"}
+		}
+		if reservedPaths[strings.ToLower(segments[0])] {
+			return "", &LinkError{Link: raw, Reason: "link points to a post or page, not a profile"}
+		}
+		handle = segments[0]
+	}
+
+	handle = strings.ToLower(handle)
+	if !handlePattern.MatchString(handle) {
+		return "", &LinkError{Link: raw, Reason: "username may only contain letters, numbers, periods and underscores (max 30)"}
+	}
+	if strings.HasPrefix(handle, ".") || strings.HasSuffix(handle, ".") || strings.Contains(handle, "..") {
+		return "", &LinkError{Link: raw, Reason: "username cannot start or end with a period or contain consecutive periods"}
+	}
+	return handle, nil
+}
+
+// isBareHandle reports whether link, which has no "@", is a username rather
+// than a URL: it has no scheme or path, and any periods in it are part of
+// the username, as in "john.doe", rather than of a domain like
+// "instagram.com".
+func isBareHandle(link string) bool {
+	if !strings.ContainsAny(link, "./:") {
+		return true
+	}
+	if strings.ContainsAny(link, "/:") {
+		return false
+	}
+	return handlePattern.MatchString(strings.ToLower(link)) && !isInstagramHost(link)
+}
+
+// isInstagramHost reports whether host is instagram.com or instagr.am, with
+// or without the www or m subdomain.
+func isInstagramHost(host string) bool {
+	host = strings.TrimPrefix(strings.ToLower(host), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	return host == "instagram.com" || host == "instagr.am"
+}
+
+// ProfileURL returns the canonical profile URL for a normalized handle.
+func ProfileURL(handle string) string {
+	return "https://www.instagram.com/" + handle + "/"
+}
@@ -0,0 +1,61 @@
+package instagram
+
+import "testing"
+
+func TestParseHandle(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"john", "john"},
+		{"John_Doe", "john_doe"},
+		{"john.doe", "john.doe"},
+		{" john.doe ", "john.doe"},
+		{"@john.doe", "john.doe"},
+		{"j.o.h.n", "j.o.h.n"},
+		{"john.com", "john.com"},
+		{"instagram.com/john.doe", "john.doe"},
+		{"www.instagram.com/john.doe/", "john.doe"},
+		{"https://www.instagram.com/John.Doe/", "john.doe"},
+		{"http://instagram.com/john?igsh=abc", "john"},
+		{"https://m.instagram.com/john/", "john"},
+		{"https://instagr.am/john", "john"},
+		{"https://www.instagram.com/john/reels/", "john"},
+	}
+	for _, tt := range tests {
+		got, err := ParseHandle(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseHandle(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+
+	for _, raw := range []string{
+		"",
+		"   ",
+		"@",
+		"instagram.com",
+		"www.instagram.com",
+		"https://www.instagram.com/",
+		"https://www.instagram.com/p/Cxyz123/",
+		"https://www.instagram.com/explore/",
+		"https://example.com/john",
+		"example.com/john",
+		"ftp://instagram.com/john",
+		".john",
+		"john.",
+		"john..doe",
+		"john doe",
+		"john-doe",
+		"@john/doe",
+		"abcdefghijklmnopqrstuvwxyz12345",
+	} {
+		if got, err := ParseHandle(raw); err == nil {
+			t.Errorf("ParseHandle(%q) = %q, want an error", raw, got)
+		}
+	}
+}
+
+func TestProfileURL(t *testing.T) {
+	if got, want := ProfileURL("john.doe"), "https://www.instagram.com/john.doe/"; got != want {
+		t.Errorf("ProfileURL = %q, want %q", got, want)
+	}
+}
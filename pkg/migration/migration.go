@@ -1,24 +1,21 @@
 package migration
 
 import (
-	"embed"
 	"log"
 
+	"github.com/cavidyrm/instawall/migrations"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
 	"github.com/jmoiron/sqlx"
 )
 
-//go:embed ../../migrations/*.sql
-var migrationsFS embed.FS
-
 // Run applies all up migrations from the embedded SQL files.
 func Run(db *sqlx.DB, dbName string) {
 	log.Println("Starting database migration...")
 
 	// 1. Create the source driver from the embedded filesystem.
-	sourceDriver, err := iofs.New(migrationsFS, "migrations")
+	sourceDriver, err := iofs.New(migrations.FS, ".")
 	if err != nil {
 		log.Fatalf("could not create migration source driver: %v", err)
 	}
@@ -0,0 +1,41 @@
+// Package mobile validates mobile numbers and puts them in the canonical
+// E.164 form they are stored and looked up in.
+package mobile
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	// iranPattern matches Iranian mobile numbers with or without the country
+	// code, e.g. 09121234567, 9121234567, 00989121234567 or +989121234567.
+	iranPattern = regexp.MustCompile(`^(?:\+98|0098|0)?(9\d{9})$`)
+	// e164Pattern matches international numbers in E.164 format.
+	e164Pattern = regexp.MustCompile(`^\+[1-9]\d{7,14}$`)
+)
+
+// ErrInvalid is returned for numbers that are neither Iranian mobile numbers
+// nor in E.164 format.
+var ErrInvalid = errors.New("not an Iranian or E.164 mobile number")
+
+// Normalize returns raw in E.164 form, so that every way of writing the same
+// number maps to one value: Iranian numbers get the +98 country code, and
+// E.164 numbers are returned as they are.
+func Normalize(raw string) (string, error) {
+	s := strings.TrimSpace(raw)
+	if m := iranPattern.FindStringSubmatch(s); m != nil {
+		return "+98" + m[1], nil
+	}
+	if e164Pattern.MatchString(s) {
+		return s, nil
+	}
+	return "", ErrInvalid
+}
+
+// Valid reports whether raw is a mobile number Normalize accepts.
+func Valid(raw string) bool {
+	_, err := Normalize(raw)
+	return err == nil
+}
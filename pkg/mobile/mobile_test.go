@@ -0,0 +1,28 @@
+package mobile
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"09121234567", "+989121234567"},
+		{"9121234567", "+989121234567"},
+		{"00989121234567", "+989121234567"},
+		{"+989121234567", "+989121234567"},
+		{" +989121234567 ", "+989121234567"},
+		{"+447911123456", "+447911123456"},
+	}
+	for _, tt := range tests {
+		got, err := Normalize(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("Normalize(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"", "0912123456", "08121234567", "+0123456789", "00447911123456", "phone"} {
+		if got, err := Normalize(raw); err == nil {
+			t.Errorf("Normalize(%q) = %q, want an error", raw, got)
+		}
+	}
+}
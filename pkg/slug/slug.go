@@ -0,0 +1,71 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxLength is the maximum length of a generated slug.
+const MaxLength = 100
+
+// transliterations maps Persian and Arabic letters and digits to Latin.
+var transliterations = map[rune]string{
+	'ا': "a", 'آ': "a", 'أ': "a", 'إ': "e", 'ٱ': "a",
+	'ب': "b", 'پ': "p", 'ت': "t", 'ث': "s", 'ج': "j", 'چ': "ch",
+	'ح': "h", 'خ': "kh", 'د': "d", 'ذ': "z", 'ر': "r", 'ز': "z",
+	'ژ': "zh", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "z", 'ط': "t",
+	'ظ': "z", 'ع': "a", 'غ': "gh", 'ف': "f", 'ق': "gh", 'ک': "k",
+	'ك': "k", 'گ': "g", 'ل': "l", 'م': "m", 'ن': "n", 'و': "v",
+	'ؤ': "o", 'ه': "h", 'ة': "h", 'ۀ': "e", 'ی': "y", 'ي': "y",
+	'ى': "a", 'ئ': "y", 'ء': "",
+	'۰': "0", '۱': "1", '۲': "2", '۳': "3", '۴': "4",
+	'۵': "5", '۶': "6", '۷': "7", '۸': "8", '۹': "9",
+	'٠': "0", '١': "1", '٢': "2", '٣': "3", '٤': "4",
+	'٥': "5", '٦': "6", '٧': "7", '٨': "8", '٩': "9",
+	'‌': "", // zero-width non-joiner
+	'ـ': "", // tatweel
+}
+
+// Make returns a URL-friendly slug for s: lower-case ASCII letters and
+// digits separated by single hyphens. Persian and Arabic text is
+// transliterated and Latin accents are stripped. The result may be empty if
+// s contains nothing that can be represented.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	write := func(str string) {
+		for _, r := range str {
+			if b.Len() >= MaxLength {
+				return
+			}
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			hyphen = false
+			// The separator may have used up the last byte; the trailing
+			// hyphen is trimmed below.
+			if b.Len() >= MaxLength {
+				return
+			}
+			b.WriteRune(r)
+		}
+	}
+
+	for _, r := range norm.NFKD.String(s) {
+		if t, ok := transliterations[r]; ok {
+			write(t)
+			continue
+		}
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Combining marks: accents and Arabic vowel signs.
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(unicode.ToLower(r)))
+		default:
+			hyphen = true
+		}
+	}
+	return strings.TrimRight(b.String(), "-")
+}
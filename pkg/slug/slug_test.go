@@ -0,0 +1,36 @@
+package slug
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"Hello, World!", "hello-world"},
+		{"  --Leading and trailing--  ", "leading-and-trailing"},
+		{"Café Crème", "cafe-creme"},
+		{"کافه تهران", "kafh-thran"},
+		{"می‌خواهم", "mykhvahm"},
+		{"شماره ۱۲۳", "shmarh-123"},
+		{"!!!", ""},
+		{"", ""},
+		{strings.Repeat("a", MaxLength+10), strings.Repeat("a", MaxLength)},
+		// The separator lands on the last byte and is trimmed.
+		{strings.Repeat("a", MaxLength-1) + " b", strings.Repeat("a", MaxLength-1)},
+		// The word after the separator is cut to fit.
+		{strings.Repeat("a", MaxLength-2) + " bcd", strings.Repeat("a", MaxLength-2) + "-b"},
+		{strings.Repeat("a", MaxLength) + " b", strings.Repeat("a", MaxLength)},
+	}
+	for _, tt := range tests {
+		got := Make(tt.s)
+		if got != tt.want {
+			t.Errorf("Make(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+		if n := len([]rune(got)); n > MaxLength {
+			t.Errorf("Make(%q) is %d runes long, want at most %d", tt.s, n, MaxLength)
+		}
+	}
+}